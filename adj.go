@@ -11,10 +11,210 @@ package graph
 // be left near their use.
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
 	"sort"
+	"time"
 )
 
+// AllShortestPaths finds all paths from start to end with a minimum number
+// of nodes.
+//
+// Paths are returned as node sequences.  Paths are distinguished by node
+// sequence so parallel arcs do not produce duplicate paths.  The result is
+// nil if end is not reachable from start.
+//
+// The number of shortest paths can be exponential in the number of nodes.
+// See CountShortestPaths for counting paths without enumerating them.
+func (g AdjacencyList) AllShortestPaths(start, end NI) (paths [][]NI) {
+	pred, dist, _ := g.shortestPathPreds(start, end)
+	if dist[end] < 0 {
+		return nil
+	}
+	p := make([]NI, dist[end]+1)
+	var f func(n NI, i int)
+	f = func(n NI, i int) {
+		p[i] = n
+		if i == 0 {
+			paths = append(paths, append([]NI{}, p...))
+			return
+		}
+		for _, fr := range pred[n] {
+			f(fr, i-1)
+		}
+	}
+	f(end, dist[end])
+	return
+}
+
+// shortestPathPreds runs a breadth first search from start, stopping at
+// end.  For each node n reached, dist[n] is the number of arcs of
+// a shortest path from start and pred[n] lists the distinct nodes that
+// precede n on shortest paths.  Unreached nodes have dist -1.  Result order
+// lists nodes reached, in order of distance.
+func (g AdjacencyList) shortestPathPreds(start, end NI) (pred [][]NI, dist []int, order []NI) {
+	pred = make([][]NI, len(g))
+	dist = make([]int, len(g))
+	last := make([]NI, len(g)) // last node to find n, to skip parallel arcs
+	for n := range dist {
+		dist[n] = -1
+		last[n] = -1
+	}
+	dist[start] = 0
+	q := []NI{start}
+	for len(q) > 0 {
+		fr := q[0]
+		q = q[1:]
+		order = append(order, fr)
+		if fr == end {
+			// all predecessors of end are closer to start and so have
+			// already been dequeued.
+			break
+		}
+		for _, to := range g[fr] {
+			if last[to] == fr {
+				continue
+			}
+			last[to] = fr
+			switch dist[to] {
+			case -1:
+				dist[to] = dist[fr] + 1
+				pred[to] = []NI{fr}
+				q = append(q, to)
+			case dist[fr] + 1:
+				pred[to] = append(pred[to], fr)
+			}
+		}
+	}
+	return
+}
+
+// ClosenessCentrality computes closeness centrality of each node of g.
+//
+// Distance is the number of arcs of a shortest path.  For each node n,
+// the result is the number of nodes reachable from n divided by the sum of
+// distances from n to those nodes, that is, the reciprocal of the average
+// distance to reachable nodes.  Nodes that reach no other nodes have
+// centrality 0.
+//
+// See LabeledAdjacencyList.ClosenessCentrality for a weighted version.
+func (g AdjacencyList) ClosenessCentrality() (cc []float64) {
+	cc = make([]float64, len(g))
+	dist := make([]int, len(g))
+	for start := range g {
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[start] = 0
+		q := []NI{NI(start)}
+		sum, r := 0, 0
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, to := range g[n] {
+				if dist[to] < 0 {
+					dist[to] = dist[n] + 1
+					sum += dist[to]
+					r++
+					q = append(q, to)
+				}
+			}
+		}
+		if r > 0 {
+			cc[start] = float64(r) / float64(sum)
+		}
+	}
+	return
+}
+
+// CountShortestPaths returns the number of paths from start to end with
+// a minimum number of nodes.
+//
+// Paths are counted as distinct node sequences, consistent with
+// AllShortestPaths.  The result is 0 if end is not reachable from start.
+// Time is proportional to the size of g even where the number of paths is
+// exponential, although in such cases the count can overflow int.
+func (g AdjacencyList) CountShortestPaths(start, end NI) int {
+	pred, dist, order := g.shortestPathPreds(start, end)
+	if dist[end] < 0 {
+		return 0
+	}
+	count := make([]int, len(g))
+	count[start] = 1
+	for _, n := range order[1:] {
+		for _, fr := range pred[n] {
+			count[n] += count[fr]
+		}
+	}
+	return count[end]
+}
+
+// DegreeSequence returns the out-degree of each node of g.
+//
+// Loops and parallel arcs each count as an arc.  For an undirected graph,
+// see Undirected.Degree and Undirected.DegreeHistogram which count loops
+// twice.
+func (g AdjacencyList) DegreeSequence() (out []int) {
+	out = make([]int, len(g))
+	for n, to := range g {
+		out[n] = len(to)
+	}
+	return
+}
+
+// Diameter returns the diameter of g, the maximum eccentricity of any node.
+//
+// As described at Eccentricity, eccentricities are computed within the
+// set of nodes reachable from each node and so are always finite.  For a
+// graph that is not strongly connected the result is the largest finite
+// eccentricity rather than infinity.  The diameter of a graph with no nodes
+// is 0.
+func (g AdjacencyList) Diameter() (d int) {
+	for _, e := range g.Eccentricity() {
+		if e > d {
+			d = e
+		}
+	}
+	return
+}
+
+// Eccentricity computes the eccentricity of each node of g.
+//
+// The eccentricity of a node n is the greatest distance from n to any node
+// reachable from n, where distance is the number of arcs of a shortest path.
+// Unreachable nodes are not considered so for a disconnected graph the
+// eccentricity of a node is computed within its reachable set.  A node that
+// reaches no other nodes has eccentricity 0.
+//
+// A breadth first search is done from each node, for time O(n(n+m)).
+//
+// See also Diameter and Radius.
+func (g AdjacencyList) Eccentricity() (ecc []int) {
+	ecc = make([]int, len(g))
+	dist := make([]int, len(g))
+	for start := range g {
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[start] = 0
+		q := []NI{NI(start)}
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, to := range g[n] {
+				if dist[to] < 0 {
+					dist[to] = dist[n] + 1
+					q = append(q, to)
+				}
+			}
+			// nodes are dequeued in order of increasing distance
+			ecc[start] = dist[n]
+		}
+	}
+	return
+}
+
 // HasParallelSort identifies if a graph contains parallel arcs, multiple arcs
 // that lead from a node to the same node.
 //
@@ -84,6 +284,310 @@ func (g AdjacencyList) IsUndirected() (u bool, from, to NI) {
 	return true, -1, -1
 }
 
+// MaximumMatching finds a maximum cardinality matching in a bipartite graph
+// using the Hopcroft-Karp algorithm.
+//
+// Argument partition gives the two sides of the bipartite graph, nodes with
+// bit 1 on one side and nodes with bit 0 on the other.  Color classes
+// returned by Undirected.Bipartite can be used for example.  Graph g should
+// be undirected, although only arcs from nodes with partition bit 1 are
+// used.  An error is returned if any arc connects two nodes on the same side
+// of the partition.
+//
+// In result matching, matching[n] is the node matched to n, or -1 if n
+// is not matched.  Result size is the number of matched pairs.
+func (g AdjacencyList) MaximumMatching(partition Bits) (matching []NI, size int, err error) {
+	for fr, to := range g {
+		p := partition.Bit(NI(fr))
+		for _, to := range to {
+			if partition.Bit(to) == p {
+				return nil, 0, fmt.Errorf("arc %d->%d within partition", fr, to)
+			}
+		}
+	}
+	matching = make([]NI, len(g))
+	for i := range matching {
+		matching[i] = -1
+	}
+	var u []NI // nodes with partition bit 1
+	partition.Iterate(func(n NI) bool {
+		if int(n) < len(g) {
+			u = append(u, n)
+		}
+		return true
+	})
+	const inf = math.MaxInt32
+	dist := make([]int, len(g))
+	// bfs layers free u nodes and returns true if an augmenting path exists
+	bfs := func() (found bool) {
+		var q []NI
+		for _, n := range u {
+			if matching[n] < 0 {
+				dist[n] = 0
+				q = append(q, n)
+			} else {
+				dist[n] = inf
+			}
+		}
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, v := range g[n] {
+				m := matching[v]
+				if m < 0 {
+					found = true
+				} else if dist[m] == inf {
+					dist[m] = dist[n] + 1
+					q = append(q, m)
+				}
+			}
+		}
+		return
+	}
+	var df func(NI) bool
+	df = func(n NI) bool {
+		for _, v := range g[n] {
+			m := matching[v]
+			if m < 0 || dist[m] == dist[n]+1 && df(m) {
+				matching[n] = v
+				matching[v] = n
+				return true
+			}
+		}
+		dist[n] = inf
+		return false
+	}
+	for bfs() {
+		for _, n := range u {
+			if matching[n] < 0 && df(n) {
+				size++
+			}
+		}
+	}
+	return
+}
+
+// MultiSourceBFS finds the distance from each node to the nearest of
+// a set of source nodes.
+//
+// Distance is the number of arcs in a shortest path from any source to
+// the node.  Result dist[n] is the distance to node n and nearest[n] is
+// the source at that distance.  Where sources are equally near, nearest
+// is the one appearing first in argument sources.  For nodes not reachable
+// from any source, dist and nearest are -1.  Sources that are not nodes
+// of g, negative or >= len(g), are ignored.
+//
+// The search is a single breadth first traversal started from all sources
+// at once, and so takes time proportional to the size of g regardless of
+// the number of sources.
+func (g AdjacencyList) MultiSourceBFS(sources []NI) (dist []int, nearest []NI) {
+	dist = make([]int, len(g))
+	nearest = make([]NI, len(g))
+	for i := range dist {
+		dist[i] = -1
+		nearest[i] = -1
+	}
+	var q []NI
+	for _, s := range sources {
+		if s >= 0 && int(s) < len(g) && dist[s] < 0 {
+			dist[s] = 0
+			nearest[s] = s
+			q = append(q, s)
+		}
+	}
+	for len(q) > 0 {
+		n := q[0]
+		q = q[1:]
+		for _, to := range g[n] {
+			if dist[to] < 0 {
+				dist[to] = dist[n] + 1
+				nearest[to] = nearest[n]
+				q = append(q, to)
+			}
+		}
+	}
+	return
+}
+
+// Quotient constructs the quotient graph of g under a partition of its nodes.
+//
+// Argument classOf must return a class number for each node of g.  Class
+// numbers must be non-negative and should be reasonably dense as the result
+// has a node for each class number from 0 to the maximum returned.
+//
+// The result has an arc from class c1 to class c2 if g has any arc from a node
+// of class c1 to a node of class c2.  Parallel arcs are not created.  Arcs
+// within a class result in a loop on the class node if argument loops is true
+// and are dropped if loops is false.
+//
+// The condensation of a graph is the special case where classes are strongly
+// connected components.
+//
+// An error is returned if classOf returns a negative number.
+func (g AdjacencyList) Quotient(classOf func(NI) int, loops bool) (AdjacencyList, error) {
+	cl := make([]NI, len(g))
+	max := -1
+	for n := range g {
+		c := classOf(NI(n))
+		if c < 0 {
+			return nil, fmt.Errorf("negative class %d for node %d", c, n)
+		}
+		if c > max {
+			max = c
+		}
+		cl[n] = NI(c)
+	}
+	q := make(AdjacencyList, max+1)
+	m := make([]Bits, max+1) // tos map for each class
+	for fr, to := range g {
+		cf := cl[fr]
+		for _, to := range to {
+			ct := cl[to]
+			if (ct != cf || loops) && m[cf].Bit(ct) == 0 {
+				m[cf].SetBit(ct, 1)
+				q[cf] = append(q[cf], ct)
+			}
+		}
+	}
+	return q, nil
+}
+
+// Radius returns the radius of g, the minimum eccentricity of any node.
+//
+// Eccentricities are as computed by Eccentricity.  Note that a node that
+// reaches no other nodes has eccentricity 0 so the radius of a graph with
+// such a node is 0.  The radius of a graph with no nodes is also 0.
+func (g AdjacencyList) Radius() (r int) {
+	ecc := g.Eccentricity()
+	if len(ecc) == 0 {
+		return 0
+	}
+	r = ecc[0]
+	for _, e := range ecc[1:] {
+		if e < r {
+			r = e
+		}
+	}
+	return
+}
+
+// RandomWalk takes a random walk on g.
+//
+// The walk starts at node start and at each step moves to a uniformly
+// chosen out-neighbor of the current node.  Parallel arcs each count as
+// a separate choice.  The walk stops after the given number of steps or
+// early at a node with no out-arcs.  The result lists the nodes of the walk,
+// starting with start, and so has length at most steps+1.
+//
+// Random numbers are drawn from src.  If src is nil, the method creates
+// a time-seeded source for one-time use.
+//
+// See LabeledAdjacencyList.WeightedRandomWalk for a weighted version.
+func (g AdjacencyList) RandomWalk(start NI, steps int, src rand.Source) (walk []NI) {
+	r := newRand(src)
+	walk = []NI{start}
+	for n := start; steps > 0 && len(g[n]) > 0; steps-- {
+		n = g[n][r.Intn(len(g[n]))]
+		walk = append(walk, n)
+	}
+	return
+}
+
+// newRand returns a generator using src, or a time-seeded source if src
+// is nil.
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	return rand.New(src)
+}
+
+// ReachableWithinHops finds, for each node, the nodes reachable within
+// a given number of steps.
+//
+// Element n of the result is a bitmap of nodes reachable from n by a path
+// of at most steps arcs.  A node is always reachable from itself in zero
+// steps.  If steps is negative, all bitmaps are empty.
+//
+// The result is computed from BitMatrix by boolean matrix powers, using
+// repeated squaring, and so takes O(log steps) matrix products.  Squaring
+// stops early when the matrix no longer changes.  For unlimited steps see
+// Directed.ReachabilityMatrix.  See LabeledDirected.ReachableWithin for
+// weighted reachability from a single node.
+func (g AdjacencyList) ReachableWithinHops(steps int) []Bits {
+	n := len(g)
+	r := make([]Bits, n)
+	if steps < 0 {
+		return r
+	}
+	base := g.BitMatrix() // paths of at most 1, then 2, 4, ... arcs
+	for i := range r {
+		r[i].SetBit(NI(i), 1)
+		base[i].SetBit(NI(i), 1)
+	}
+	for steps > 0 {
+		if steps&1 == 1 {
+			r = boolMatMul(r, base)
+		}
+		if steps >>= 1; steps == 0 {
+			break
+		}
+		sq := boolMatMul(base, base)
+		same := true
+		for i := range sq {
+			if !sq[i].Eq(base[i]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			// base is now the full reflexive transitive closure
+			return boolMatMul(r, base)
+		}
+		base = sq
+	}
+	return r
+}
+
+// boolMatMul returns the boolean matrix product of x and y.
+func boolMatMul(x, y []Bits) []Bits {
+	p := make([]Bits, len(x))
+	for i := range x {
+		x[i].Iterate(func(j NI) bool {
+			p[i].Or(p[i], y[j])
+			return true
+		})
+	}
+	return p
+}
+
+// ClosenessCentrality computes closeness centrality of each node of g
+// using weighted distances.
+//
+// Distance is the sum of arc weights of a shortest path, as found by
+// Dijkstra.  Arc weights must be non-negative.  For each node n, the result
+// is the number of nodes reachable from n divided by the sum of distances
+// from n to those nodes.  Nodes that reach no other nodes have centrality 0.
+//
+// See AdjacencyList.ClosenessCentrality for an unweighted version.
+func (g LabeledAdjacencyList) ClosenessCentrality(w WeightFunc) (cc []float64) {
+	cc = make([]float64, len(g))
+	for start := range g {
+		_, dist, _ := g.Dijkstra(NI(start), -1, w)
+		sum, r := 0., 0
+		for n, d := range dist {
+			if n != start && !math.IsInf(d, 1) {
+				sum += d
+				r++
+			}
+		}
+		if r > 0 {
+			cc[start] = float64(r) / sum
+		}
+	}
+	return
+}
+
 // Edgelist constructs the edge list rerpresentation of a graph.
 //
 // An edge is returned for each arc of the graph.  For undirected graphs
@@ -119,6 +623,50 @@ func (g LabeledAdjacencyList) FloydWarshall(w WeightFunc) (d [][]float64) {
 	return
 }
 
+// FloydWarshallPaths finds all pairs shortest distances and paths for a
+// weighted graph without negative cycles.
+//
+// Result d is as for FloydWarshall.  In result p, p[i][j] is the node
+// preceding j on a shortest path from i to j, or -1 if there is no path
+// or if i == j.  A path from i to j can be recovered by following p[i]
+// back from j until reaching i.
+//
+// Unlike FloydWarshall, parallel arcs are allowed.  The minimum weight arc
+// is used.
+func (g LabeledAdjacencyList) FloydWarshallPaths(w WeightFunc) (d [][]float64, p [][]NI) {
+	d = newFWd(len(g))
+	p = make([][]NI, len(g))
+	for i := range p {
+		pi := make([]NI, len(g))
+		for j := range pi {
+			pi[j] = -1
+		}
+		p[i] = pi
+	}
+	for fr, to := range g {
+		for _, to := range to {
+			if wt := w(to.Label); wt < d[fr][to.To] {
+				d[fr][to.To] = wt
+				p[fr][to.To] = NI(fr)
+			}
+		}
+	}
+	for k, dk := range d {
+		pk := p[k]
+		for i, di := range d {
+			dik := di[k]
+			pi := p[i]
+			for j := range d {
+				if d2 := dik + dk[j]; d2 < di[j] {
+					di[j] = d2
+					pi[j] = pk[j]
+				}
+			}
+		}
+	}
+	return
+}
+
 // little helper function, makes a blank matrix for FloydWarshall.
 func newFWd(n int) [][]float64 {
 	d := make([][]float64, n)
@@ -250,6 +798,48 @@ func (g LabeledAdjacencyList) NegativeArc(w WeightFunc) bool {
 	return false
 }
 
+// SampleNeighbor chooses an arc from node n with probability proportional
+// to arc weight.
+//
+// Weights are returned by w.  Arcs with weight zero or less are never
+// chosen.  Returned is the chosen arc as a Half, and ok = true.  If n has
+// no arcs of positive weight, SampleNeighbor returns ok = false.
+//
+// The method scans the arcs of n twice, once to total the weights and once
+// to select an arc, consuming a single random number from src.  For a given
+// graph and sequence of random numbers the result is deterministic.
+//
+// If src is nil, the method creates a time-seeded source for one-time use.
+func (g LabeledAdjacencyList) SampleNeighbor(n NI, w WeightFunc, src rand.Source) (h Half, ok bool) {
+	return g.sampleNeighbor(n, w, newRand(src))
+}
+
+func (g LabeledAdjacencyList) sampleNeighbor(n NI, w WeightFunc, r *rand.Rand) (h Half, ok bool) {
+	total := 0.
+	for _, nb := range g[n] {
+		if wt := w(nb.Label); wt > 0 {
+			total += wt
+		}
+	}
+	if !(total > 0) {
+		return
+	}
+	x := r.Float64() * total
+	for _, nb := range g[n] {
+		wt := w(nb.Label)
+		if !(wt > 0) {
+			continue
+		}
+		// the last positive arc takes any remainder from rounding
+		h, ok = nb, true
+		if x < wt {
+			return
+		}
+		x -= wt
+	}
+	return
+}
+
 // Unlabeled constructs the unlabeled graph corresponding to g.
 func (g LabeledAdjacencyList) Unlabeled() AdjacencyList {
 	a := make(AdjacencyList, len(g))
@@ -315,6 +905,34 @@ func (g LabeledAdjacencyList) WeightedOutDegree(n NI, w WeightFunc) (d float64)
 	return
 }
 
+// WeightedRandomWalk takes a random walk on g, choosing arcs with
+// probability proportional to weight.
+//
+// The walk starts at node start and at each step follows an arc from the
+// current node chosen with probability proportional to its weight, as
+// returned by w.  Arcs with weight zero or less are never chosen.  The walk
+// stops after the given number of steps or early at a node with no arcs of
+// positive weight.  The result lists the nodes of the walk, starting with
+// start, and so has length at most steps+1.
+//
+// Random numbers are drawn from src.  If src is nil, the method creates
+// a time-seeded source for one-time use.
+//
+// See AdjacencyList.RandomWalk for an unweighted version.
+func (g LabeledAdjacencyList) WeightedRandomWalk(start NI, steps int, w WeightFunc, src rand.Source) (walk []NI) {
+	r := newRand(src)
+	walk = []NI{start}
+	for n := start; steps > 0; steps-- {
+		h, ok := g.sampleNeighbor(n, w, r)
+		if !ok {
+			break
+		}
+		n = h.To
+		walk = append(walk, n)
+	}
+	return
+}
+
 // More about loops and strength:  I didn't see consensus on this especially
 // in the case of undirected graphs.  Some sources said to add in-degree and
 // out-degree, which would seemingly double both loops and non-loops.
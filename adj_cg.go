@@ -12,6 +12,45 @@ import (
 	"time"
 )
 
+// AllSimplePaths enumerates all simple paths from start to end.
+//
+// A simple path is one with no repeated nodes.  The method calls emit for
+// each path found as long as emit returns true.  If emit returns false,
+// AllSimplePaths returns immediately.  Each path is a newly allocated slice
+// beginning with start and ending with end.  If start == end, the single
+// path emitted is the single node start.
+//
+// Parallel arcs give distinct paths.  A node sequence is emitted once for
+// each combination of parallel arcs along it, so the same sequence may be
+// emitted more than once.
+//
+// The number of simple paths can be exponential in the size of the graph.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) AllSimplePaths(start, end NI, emit func([]NI) bool) {
+	var onPath Bits
+	var p []NI
+	var df func(NI) bool
+	df = func(n NI) bool {
+		p = append(p, n)
+		if n == end {
+			ok := emit(append([]NI{}, p...))
+			p = p[:len(p)-1]
+			return ok
+		}
+		onPath.SetBit(n, 1)
+		for _, nb := range g[n] {
+			if onPath.Bit(nb.To) == 0 && !df(nb.To) {
+				return false
+			}
+		}
+		onPath.SetBit(n, 0)
+		p = p[:len(p)-1]
+		return true
+	}
+	df(start)
+}
+
 // ArcDensity returns density for an simple directed graph.
 //
 // See also ArcDensity function.
@@ -37,6 +76,26 @@ func (g LabeledAdjacencyList) ArcSize() int {
 	return m
 }
 
+// BitMatrix returns the adjacency matrix of g as a bitmap per node.
+//
+// Bit j of element i of the result is 1 when g has an arc from i to j.
+// Parallel arcs are represented as single arcs.
+//
+// Bitmap rows allow set operations on neighborhoods with And and Or.
+// See TransitiveClosure and ReachabilityMatrix of the Directed types
+// for reachability.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) BitMatrix() []Bits {
+	m := make([]Bits, len(g))
+	for fr, to := range g {
+		for _, to := range to {
+			m[fr].SetBit(to.To, 1)
+		}
+	}
+	return m
+}
+
 // BoundsOk validates that all arcs in g stay within the slice bounds of g.
 //
 // BoundsOk returns true when no arcs point outside the bounds of g.
@@ -146,6 +205,11 @@ func (g LabeledAdjacencyList) BreadthFirst(start NI, r *rand.Rand, f *FromList,
 // Returned is the path as list of nodes.
 // The result is nil if no path was found.
 //
+// This is the shortest path in an unweighted graph.  The number of arcs
+// in the path, or hop count, is len(path)-1.  Arc weights are not
+// considered; see Dijkstra for weighted shortest paths.  See also
+// ShortestPath, which returns the hop count directly.
+//
 // There are equivalent labeled and unlabeled versions of this method.
 func (g LabeledAdjacencyList) BreadthFirstPath(start, end NI) []NI {
 	var f FromList
@@ -153,6 +217,18 @@ func (g LabeledAdjacencyList) BreadthFirstPath(start, end NI) []NI {
 	return f.PathTo(end, nil)
 }
 
+// ShortestPath finds a path from start to end with a minimum number of arcs.
+//
+// It is BreadthFirstPath with results for convenience.  Returned length is
+// the number of arcs in the path, the hop count.  If end is not reachable
+// from start, found is false, path is nil, and length is -1.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) ShortestPath(start, end NI) (path []NI, length int, found bool) {
+	path = g.BreadthFirstPath(start, end)
+	return path, len(path) - 1, path != nil
+}
+
 // Copy makes a deep copy of g.
 // Copy also computes the arc size ma, the number of arcs.
 //
@@ -253,6 +329,139 @@ func (g LabeledAdjacencyList) DepthFirstRandom(start NI, bm *Bits, v OkNodeVisit
 	return df(start)
 }
 
+// Equal returns true if g and h have exactly the same structure.
+//
+// That is, g and h must have the same number of nodes and for each node the
+// same arcs in the same order.  For labeled graphs, arc labels must also be
+// equal.  See EqualUnordered for a comparison that ignores arc order.
+//
+// Equal compares representations, it does not test for isomorphism.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) Equal(h LabeledAdjacencyList) bool {
+	if len(g) != len(h) {
+		return false
+	}
+	for n, gt := range g {
+		ht := h[n]
+		if len(gt) != len(ht) {
+			return false
+		}
+		for x, nb := range gt {
+			if nb != ht[x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EqualUnordered returns true if g and h have the same arcs, regardless of
+// order.
+//
+// That is, g and h must have the same number of nodes and for each node the
+// arcs of g and h must be equal as multisets.  For labeled graphs, arcs are
+// equal if they have the same to node and the same label.  See Equal for
+// a comparison that requires the same order.
+//
+// Like Equal, EqualUnordered does not test for isomorphism.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) EqualUnordered(h LabeledAdjacencyList) bool {
+	if len(g) != len(h) {
+		return false
+	}
+	count := map[Half]int{}
+	for n, gt := range g {
+		ht := h[n]
+		if len(gt) != len(ht) {
+			return false
+		}
+		for _, nb := range gt {
+			count[nb]++
+		}
+		for _, nb := range ht {
+			c := count[nb] - 1
+			if c < 0 {
+				return false
+			}
+			count[nb] = c
+		}
+		// all counts are now zero and can be reused for the next node
+	}
+	return true
+}
+
+// HamiltonianPath finds a path visiting every node of g exactly once.
+//
+// Arcs are followed in their direction so g may be directed or undirected.
+// Start nodes are tried in order.  If a path is found it is returned with
+// ok = true.  Otherwise the result is nil, false.
+//
+// The search is by backtracking and the time can be exponential in the
+// number of nodes.  It is practical for graphs of a few dozen nodes.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) HamiltonianPath() (path []NI, ok bool) {
+	for n := range g {
+		if path, ok = g.HamiltonianPathFrom(NI(n)); ok {
+			return
+		}
+	}
+	return nil, false
+}
+
+// HamiltonianPathFrom finds a path starting at node start and visiting every
+// node of g exactly once.
+//
+// See HamiltonianPath.  The search prunes a partial path when some node
+// not yet on the path can no longer be reached from the end of the path
+// through nodes not on the path.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) HamiltonianPathFrom(start NI) (path []NI, ok bool) {
+	var onPath, r Bits
+	// reachable tests if all nodes not on path are reachable from n.
+	reachable := func(n NI) bool {
+		r.Set(onPath)
+		c := 0
+		var df func(NI)
+		df = func(n NI) {
+			for _, nb := range g[n] {
+				if r.Bit(nb.To) == 0 {
+					r.SetBit(nb.To, 1)
+					c++
+					df(nb.To)
+				}
+			}
+		}
+		df(n)
+		return c == len(g)-len(path)
+	}
+	var df func(NI) bool
+	df = func(n NI) bool {
+		path = append(path, n)
+		onPath.SetBit(n, 1)
+		if len(path) == len(g) {
+			return true
+		}
+		if reachable(n) {
+			for _, nb := range g[n] {
+				if onPath.Bit(nb.To) == 0 && df(nb.To) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		onPath.SetBit(n, 0)
+		return false
+	}
+	if df(start) {
+		return path, true
+	}
+	return nil, false
+}
+
 // HasArc returns true if g has any arc from node fr to node to.
 //
 // Also returned is the index within the slice of arcs from node fr.
@@ -321,6 +530,35 @@ func (g LabeledAdjacencyList) HasParallelMap() (has bool, fr, to NI) {
 	return false, -1, -1
 }
 
+// InducedSubgraph returns the subgraph of g induced by a node set.
+//
+// The subgraph has the nodes of argument nodes and the arcs of g between
+// those nodes.  Nodes are renumbered to 0 through len(nodeMap)-1 in the
+// order they appear in argument nodes, ignoring any repeated nodes.  In
+// result nodeMap, nodeMap[n] is the node of g corresponding to node n of
+// sub.  Arcs of sub are in the same order as in g.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) InducedSubgraph(nodes []NI) (sub LabeledAdjacencyList, nodeMap []NI) {
+	newNI := make(map[NI]NI, len(nodes))
+	for _, n := range nodes {
+		if _, ok := newNI[n]; !ok {
+			newNI[n] = NI(len(nodeMap))
+			nodeMap = append(nodeMap, n)
+		}
+	}
+	sub = make(LabeledAdjacencyList, len(nodeMap))
+	for n, o := range nodeMap {
+		for _, nb := range g[o] {
+			if to, ok := newNI[nb.To]; ok {
+				nb.To = to
+				sub[n] = append(sub[n], nb)
+			}
+		}
+	}
+	return
+}
+
 // IsSimple checks for loops and parallel arcs.
 //
 // A graph is "simple" if it has no loops or parallel arcs.
@@ -360,6 +598,111 @@ func (g LabeledAdjacencyList) IsolatedNodes() (i Bits) {
 	return
 }
 
+// LoopErasedWalk returns a loop-erased random walk from start to end.
+//
+// The walk follows arcs chosen uniformly at random until reaching end.
+// Loops are erased as they form so the returned path is simple.  It begins
+// with start and ends with end.
+//
+// The walk terminates only if end is reachable from every node the walk
+// can visit.  If end is not reachable from start, or if some node reachable
+// from start cannot reach end, LoopErasedWalk returns nil.  For undirected
+// graphs this just means start and end must be connected.
+//
+// If Rand r is nil, the method creates a new source and generator for
+// one-time use.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) LoopErasedWalk(start, end NI, r *rand.Rand) []NI {
+	// nodes reachable from start without passing through end.  end is
+	// marked first so the search stops there.
+	fwd := NewBits(end)
+	g.DepthFirst(start, &fwd, nil)
+	// nodes that can reach end, by search of the transpose
+	tr := make([][]NI, len(g))
+	for fr, to := range g {
+		for _, to := range to {
+			tr[to.To] = append(tr[to.To], NI(fr))
+		}
+	}
+	var rev Bits
+	var df func(NI)
+	df = func(n NI) {
+		rev.SetBit(n, 1)
+		for _, fr := range tr[n] {
+			if rev.Bit(fr) == 0 {
+				df(fr)
+			}
+		}
+	}
+	df(end)
+	// every node the walk can visit, start included, must reach end
+	fwd.AndNot(fwd, rev)
+	if !fwd.Zero() {
+		return nil
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	next := make([]NI, len(g))
+	g.lastExitWalk(start, NewBits(end), next, r)
+	p := []NI{start}
+	for n := start; n != end; {
+		n = next[n]
+		p = append(p, n)
+	}
+	return p
+}
+
+// lastExitWalk walks randomly from start until reaching a node in stop.
+//
+// For each node visited it records in next the node reached on the last
+// exit from it.  Following next from start then gives the loop-erased walk.
+// All nodes visited must have arcs.
+func (g LabeledAdjacencyList) lastExitWalk(start NI, stop Bits, next []NI, r *rand.Rand) {
+	for n := start; stop.Bit(n) == 0; n = next[n] {
+		to := g[n]
+		next[n] = to[r.Intn(len(to))].To
+	}
+}
+
+// SimplePathCount counts simple paths of a given length from start to end.
+//
+// A simple path is one with no repeated nodes.  Length is the number of arcs
+// in the path.  Unlike a count of walks, which could be computed from powers
+// of the adjacency matrix, paths that revisit a node are not counted.
+// Parallel arcs give distinct paths.
+//
+// The count is done by depth first search and the time can be exponential
+// in length.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledAdjacencyList) SimplePathCount(start, end NI, length int) int64 {
+	var onPath Bits
+	var df func(NI, int) int64
+	df = func(n NI, l int) int64 {
+		if l == 0 {
+			if n == end {
+				return 1
+			}
+			return 0
+		}
+		if n == end {
+			return 0
+		}
+		onPath.SetBit(n, 1)
+		var c int64
+		for _, nb := range g[n] {
+			if onPath.Bit(nb.To) == 0 {
+				c += df(nb.To, l-1)
+			}
+		}
+		onPath.SetBit(n, 0)
+		return c
+	}
+	return df(start, length)
+}
+
 /*
 MaxmimalClique finds a maximal clique containing the node n.
 
@@ -18,6 +18,26 @@ import (
 	"github.com/soniakeys/graph"
 )
 
+func ExampleLabeledAdjacencyList_AllSimplePaths() {
+	// K4, the complete graph on 4 nodes
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}, {To: 3}},
+		1: {{To: 0}, {To: 2}, {To: 3}},
+		2: {{To: 0}, {To: 1}, {To: 3}},
+		3: {{To: 0}, {To: 1}, {To: 2}},
+	}
+	g.AllSimplePaths(0, 3, func(p []graph.NI) bool {
+		fmt.Println(p)
+		return true
+	})
+	// Output:
+	// [0 1 2 3]
+	// [0 1 3]
+	// [0 2 1 3]
+	// [0 2 3]
+	// [0 3]
+}
+
 func ExampleLabeledAdjacencyList_ArcDensity() {
 	// 0-->1
 	// |
@@ -65,6 +85,21 @@ func ExampleLabeledAdjacencyList_ArcSize() {
 	// 5
 }
 
+func ExampleLabeledAdjacencyList_BitMatrix() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 7}, {To: 1, Label: 9}},
+		1: {{To: 2, Label: 8}},
+		2: {},
+	}
+	for n, b := range g.BitMatrix() {
+		fmt.Println(n, b.Slice())
+	}
+	// Output:
+	// 0 [1]
+	// 1 [2]
+	// 2 []
+}
+
 func ExampleLabeledAdjacencyList_BoundsOk() {
 	var g graph.LabeledAdjacencyList
 	ok, _, _ := g.BoundsOk() // zero value adjacency list is valid
@@ -96,6 +131,30 @@ func ExampleLabeledAdjacencyList_BreadthFirstPath() {
 	// [1 4 3]
 }
 
+func ExampleLabeledAdjacencyList_BreadthFirstPath_hops() {
+	// arcs are directed right:
+	//    1   3---5
+	//   / \ /   /
+	//  2   4---6--\
+	//           \-/
+	g := graph.LabeledAdjacencyList{
+		2: {{To: 1}},
+		1: {{To: 4}},
+		4: {{To: 3}, {To: 6}},
+		3: {{To: 5}},
+		6: {{To: 5}, {To: 6}},
+	}
+	if p := g.BreadthFirstPath(2, 5); p != nil {
+		fmt.Println("path:", p, "hops:", len(p)-1)
+	}
+	if p := g.BreadthFirstPath(5, 2); p == nil {
+		fmt.Println("2 not reachable from 5")
+	}
+	// Output:
+	// path: [2 1 4 3 5] hops: 4
+	// 2 not reachable from 5
+}
+
 func ExampleLabeledAdjacencyList_BreadthFirst_singlePath() {
 	// arcs are directed right:
 	//    1   3---5
@@ -338,6 +397,125 @@ func ExampleLabeledAdjacencyList_DepthFirstRandom() {
 	// visit 8
 }
 
+func ExampleLabeledAdjacencyList_Equal() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 'a'}, {To: 2, Label: 'b'}},
+		1: {{To: 2, Label: 'c'}},
+		2: {},
+	}
+	h := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 'a'}, {To: 2, Label: 'b'}},
+		1: {{To: 2, Label: 'x'}},
+		2: {},
+	}
+	fmt.Println(g.Equal(g))
+	fmt.Println(g.Equal(h))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleLabeledAdjacencyList_EqualUnordered() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 'a'}, {To: 2, Label: 'b'}},
+		1: {{To: 2, Label: 'c'}},
+		2: {},
+	}
+	h := graph.LabeledAdjacencyList{
+		0: {{To: 2, Label: 'b'}, {To: 1, Label: 'a'}},
+		1: {{To: 2, Label: 'c'}},
+		2: {},
+	}
+	fmt.Println(g.EqualUnordered(h))
+	h[0][0].Label = 'a'
+	fmt.Println(g.EqualUnordered(h))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleLabeledAdjacencyList_HamiltonianPath() {
+	// 0---1---2
+	// |   |   |
+	// 3---4   5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 3}},
+		1: {{To: 0}, {To: 2}, {To: 4}},
+		2: {{To: 1}, {To: 5}},
+		3: {{To: 0}, {To: 4}},
+		4: {{To: 1}, {To: 3}},
+		5: {{To: 2}},
+	}
+	fmt.Println(g.HamiltonianPath())
+	fmt.Println(g.HamiltonianPathFrom(1))
+	// Output:
+	// [0 3 4 1 2 5] true
+	// [] false
+}
+
+func TestLabeledAdjacencyList_HamiltonianPath(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(7)
+		g := make(graph.LabeledAdjacencyList, n)
+		for fr := range g {
+			for to := 0; to < n; to++ {
+				if r.Intn(3) == 0 {
+					g[fr] = append(g[fr], graph.Half{To: graph.NI(to)})
+				}
+			}
+		}
+		p, ok := g.HamiltonianPath()
+		// brute force over permutations
+		want := false
+		perm := make([]graph.NI, n)
+		var try func(int, graph.Bits)
+		try = func(x int, used graph.Bits) {
+			if want {
+				return
+			}
+			if x == n {
+				want = true
+				return
+			}
+			for to := graph.NI(0); int(to) < n; to++ {
+				if used.Bit(to) == 1 {
+					continue
+				}
+				if x > 0 {
+					if h, _ := g.HasArc(perm[x-1], to); !h {
+						continue
+					}
+				}
+				perm[x] = to
+				var u graph.Bits
+				u.Set(used)
+				u.SetBit(to, 1)
+				try(x+1, u)
+			}
+		}
+		try(0, graph.Bits{})
+		if ok != want {
+			t.Fatal(g, "found", ok, "want", want)
+		}
+		if !ok {
+			continue
+		}
+		var b graph.Bits
+		for x, nd := range p {
+			b.SetBit(nd, 1)
+			if x > 0 {
+				if h, _ := g.HasArc(p[x-1], nd); !h {
+					t.Fatal(g, "path", p, "missing arc")
+				}
+			}
+		}
+		if len(p) != n || b.PopCount() != n {
+			t.Fatal(g, "path", p, "not Hamiltonian")
+		}
+	}
+}
+
 func ExampleLabeledAdjacencyList_HasArc() {
 	g := graph.LabeledAdjacencyList{
 		2: {{To: 0}, {To: 2}, {To: 0}, {To: 1}, {To: 1}},
@@ -385,6 +563,29 @@ func ExampleLabeledAdjacencyList_HasParallelMap_noParallelArcs() {
 	// false -1 -1
 }
 
+func ExampleLabeledAdjacencyList_InducedSubgraph() {
+	//   0-->1
+	//   ^\  |\
+	//   | v v v
+	//   \--2-->3
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		1: {{To: 2, Label: 2}, {To: 3, Label: 3}},
+		2: {{To: 0, Label: 4}, {To: 3, Label: 5}},
+		3: {},
+	}
+	sub, nodeMap := g.InducedSubgraph([]graph.NI{3, 2, 0})
+	fmt.Println("node map:", nodeMap)
+	for fr, to := range sub {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// node map: [3 2 0]
+	// 0 []
+	// 1 [{2 4} {0 5}]
+	// 2 [{1 1}]
+}
+
 func ExampleLabeledAdjacencyList_IsolatedNodes() {
 	//   0  1
 	//  / \
@@ -439,3 +640,116 @@ func ExampleLabeledAdjacencyList_IsSimple_parallelArc() {
 	// Output:
 	// false 2
 }
+
+func ExampleLabeledAdjacencyList_LoopErasedWalk() {
+	// 0--1--2
+	// |  |  |
+	// 3--4--5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 3}},
+		1: {{To: 0}, {To: 2}, {To: 4}},
+		2: {{To: 1}, {To: 5}},
+		3: {{To: 0}, {To: 4}},
+		4: {{To: 1}, {To: 3}, {To: 5}},
+		5: {{To: 2}, {To: 4}},
+	}
+	fmt.Println(g.LoopErasedWalk(0, 5, rand.New(rand.NewSource(7))))
+	// Output:
+	// [0 3 4 5]
+}
+
+func TestLabeledAdjacencyList_LoopErasedWalk(t *testing.T) {
+	// 0--1--2
+	// |  |  |
+	// 3--4--5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 3}},
+		1: {{To: 0}, {To: 2}, {To: 4}},
+		2: {{To: 1}, {To: 5}},
+		3: {{To: 0}, {To: 4}},
+		4: {{To: 1}, {To: 3}, {To: 5}},
+		5: {{To: 2}, {To: 4}},
+	}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		p := g.LoopErasedWalk(0, 5, r)
+		if p[0] != 0 || p[len(p)-1] != 5 {
+			t.Fatal("wrong endpoints:", p)
+		}
+		var b graph.Bits
+		for j, n := range p {
+			if b.Bit(n) == 1 {
+				t.Fatal("path not simple:", p)
+			}
+			b.SetBit(n, 1)
+			if j > 0 {
+				if ok, _ := g.HasArc(p[j-1], n); !ok {
+					t.Fatal("not a path:", p)
+				}
+			}
+		}
+	}
+	// directed, 1 is a dead end
+	d := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		2: {{To: 3}},
+		3: {},
+	}
+	if p := d.LoopErasedWalk(0, 3, r); p != nil {
+		t.Fatal("expected nil, got", p)
+	}
+	// nodes past end, here 2, do not matter
+	c := graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 2}},
+		2: {},
+	}
+	if p := c.LoopErasedWalk(0, 1, r); fmt.Sprint(p) != "[0 1]" {
+		t.Fatal("expected [0 1], got", p)
+	}
+	if p := c.LoopErasedWalk(1, 1, r); fmt.Sprint(p) != "[1]" {
+		t.Fatal("expected [1], got", p)
+	}
+	if p := c.LoopErasedWalk(1, 0, r); p != nil {
+		t.Fatal("expected nil, got", p)
+	}
+}
+
+func ExampleLabeledAdjacencyList_ShortestPath() {
+	// arcs are directed right:
+	//    1   3---5
+	//   / \ /   /
+	//  2   4---6--\
+	//           \-/
+	g := graph.LabeledAdjacencyList{
+		2: {{To: 1}},
+		1: {{To: 4}},
+		4: {{To: 3}, {To: 6}},
+		3: {{To: 5}},
+		6: {{To: 5}, {To: 6}},
+	}
+	fmt.Println(g.ShortestPath(2, 5))
+	fmt.Println(g.ShortestPath(5, 2))
+	// Output:
+	// [2 1 4 3 5] 4 true
+	// [] -1 false
+}
+
+func ExampleLabeledAdjacencyList_SimplePathCount() {
+	// K4, the complete graph on 4 nodes
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}, {To: 3}},
+		1: {{To: 0}, {To: 2}, {To: 3}},
+		2: {{To: 0}, {To: 1}, {To: 3}},
+		3: {{To: 0}, {To: 1}, {To: 2}},
+	}
+	for l := 0; l <= 4; l++ {
+		fmt.Println(l, g.SimplePathCount(0, 3, l))
+	}
+	// Output:
+	// 0 0
+	// 1 1
+	// 2 2
+	// 3 2
+	// 4 0
+}
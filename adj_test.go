@@ -5,10 +5,181 @@ package graph_test
 
 import (
 	"fmt"
+	"math/rand"
+	"testing"
 
 	"github.com/soniakeys/graph"
 )
 
+func ExampleAdjacencyList_AllShortestPaths() {
+	// arcs are directed right:
+	//      1   4
+	//     / \ / \
+	//    0   3   6
+	//     \ / \ /
+	//      2   5
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {3},
+		3: {4, 5},
+		4: {6},
+		5: {6},
+		6: {},
+	}
+	for _, p := range g.AllShortestPaths(0, 6) {
+		fmt.Println(p)
+	}
+	fmt.Println(g.AllShortestPaths(6, 0))
+	// Output:
+	// [0 1 3 4 6]
+	// [0 2 3 4 6]
+	// [0 1 3 5 6]
+	// [0 2 3 5 6]
+	// []
+}
+
+func ExampleAdjacencyList_ClosenessCentrality() {
+	// 0---1---2---3   4
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {0, 2},
+		2: {1, 3},
+		3: {2},
+		4: {},
+	}
+	for n, c := range g.ClosenessCentrality() {
+		fmt.Printf("%d %.3f\n", n, c)
+	}
+	// Output:
+	// 0 0.500
+	// 1 0.750
+	// 2 0.750
+	// 3 0.500
+	// 4 0.000
+}
+
+func ExampleAdjacencyList_CountShortestPaths() {
+	// arcs are directed right:
+	//      1   4
+	//     / \ / \
+	//    0   3   6
+	//     \ / \ /
+	//      2   5
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {3},
+		3: {4, 5},
+		4: {6},
+		5: {6},
+		6: {},
+	}
+	fmt.Println(g.CountShortestPaths(0, 6))
+	fmt.Println(g.CountShortestPaths(6, 0))
+	// Output:
+	// 4
+	// 0
+}
+
+func TestAllShortestPaths(t *testing.T) {
+	r := rand.New(rand.NewSource(62))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(12)
+		g := make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.NI(r.Intn(n)))
+		}
+		start, end := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+		paths := g.AllShortestPaths(start, end)
+		if c := g.CountShortestPaths(start, end); c != len(paths) {
+			t.Fatal("count", c, "paths", len(paths))
+		}
+		bp := g.BreadthFirstPath(start, end)
+		if bp == nil {
+			if paths != nil {
+				t.Fatal("paths", paths, "but no BreadthFirstPath")
+			}
+			continue
+		}
+		seen := map[string]bool{}
+		for _, p := range paths {
+			if len(p) != len(bp) || p[0] != start || p[len(p)-1] != end {
+				t.Fatal("path", p, "BreadthFirstPath", bp)
+			}
+			for x := 1; x < len(p); x++ {
+				if ok, _ := g.HasArc(p[x-1], p[x]); !ok {
+					t.Fatal("path", p, "missing arc")
+				}
+			}
+			if seen[fmt.Sprint(p)] {
+				t.Fatal("duplicate path", p)
+			}
+			seen[fmt.Sprint(p)] = true
+		}
+		// brute force enumeration of node sequences of the same length
+		all := map[string]bool{}
+		w := make([]graph.NI, len(bp))
+		var f func(x int)
+		f = func(x int) {
+			if x == len(w) {
+				if w[x-1] == end {
+					all[fmt.Sprint(w)] = true
+				}
+				return
+			}
+			for _, to := range g[w[x-1]] {
+				w[x] = to
+				f(x + 1)
+			}
+		}
+		w[0] = start
+		f(1)
+		if len(all) != len(paths) {
+			t.Fatal(len(paths), "paths, want", len(all))
+		}
+	}
+}
+
+func ExampleAdjacencyList_DegreeSequence() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {1},
+		2: {3, 3, 0},
+		3: {},
+	}
+	fmt.Println(g.DegreeSequence())
+	// Output:
+	// [2 1 3 0]
+}
+
+func ExampleAdjacencyList_Diameter() {
+	//   0--1--2--3    4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(4, 5)
+	fmt.Println(g.Diameter())
+	// Output:
+	// 3
+}
+
+func ExampleAdjacencyList_Eccentricity() {
+	//   0--1--2--3    4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(4, 5)
+	fmt.Println("node:", []int{0, 1, 2, 3, 4, 5})
+	fmt.Println("ecc: ", g.Eccentricity())
+	// Output:
+	// node: [0 1 2 3 4 5]
+	// ecc:  [3 2 2 3 1 1]
+}
+
 func ExampleAdjacencyList_HasParallelSort_parallelArcs() {
 	g := graph.AdjacencyList{
 		1: {0, 0},
@@ -54,6 +225,285 @@ func ExampleAdjacencyList_IsUndirected() {
 	// false 2 1
 }
 
+func ExampleAdjacencyList_MaximumMatching() {
+	// 0   1   2
+	// | \ |  /|
+	// |  \| / |
+	// 3   4   5
+	var g graph.Undirected
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(1, 4)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	p := graph.NewBits(0, 1, 2)
+	m, size, err := g.AdjacencyList.MaximumMatching(p)
+	fmt.Println(m, size, err)
+	g.AddEdge(1, 2)
+	_, _, err = g.AdjacencyList.MaximumMatching(p)
+	fmt.Println(err)
+	// Output:
+	// [3 4 5 0 1 2] 3 <nil>
+	// arc 1->2 within partition
+}
+
+func TestMaximumMatching(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		// nodes 0..nu-1 on partition side 1, nu..n-1 on side 0
+		nu := 1 + r.Intn(8)
+		n := nu + 1 + r.Intn(8)
+		var g graph.Undirected
+		var p graph.Bits
+		p.SetAll(nu)
+		// flow network: node n is source, n+1 is sink, unit capacities
+		fn := make(graph.LabeledAdjacencyList, n+2)
+		for u := 0; u < nu; u++ {
+			fn[n] = append(fn[n], graph.Half{To: graph.NI(u)})
+			for v := nu; v < n; v++ {
+				if r.Intn(3) == 0 {
+					g.AddEdge(graph.NI(u), graph.NI(v))
+					fn[u] = append(fn[u], graph.Half{To: graph.NI(v)})
+				}
+			}
+		}
+		for v := nu; v < n; v++ {
+			fn[v] = append(fn[v], graph.Half{To: graph.NI(n + 1)})
+		}
+		for len(g.AdjacencyList) < n {
+			g.AdjacencyList = append(g.AdjacencyList, nil)
+		}
+		m, size, err := g.AdjacencyList.MaximumMatching(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := graph.LabeledDirected{fn}.MaxFlow(graph.NI(n),
+			graph.NI(n+1), func(graph.LI) float64 { return 1 })
+		if float64(size) != want {
+			t.Fatal("size", size, "want", want)
+		}
+		matched := 0
+		for a, b := range m {
+			if b < 0 {
+				continue
+			}
+			matched++
+			if m[b] != graph.NI(a) {
+				t.Fatal("matching not symmetric", a, b)
+			}
+			if ok, _ := g.HasArc(graph.NI(a), b); !ok {
+				t.Fatal("matched nodes", a, b, "not adjacent")
+			}
+		}
+		if matched != 2*size {
+			t.Fatal("matched", matched, "size", size)
+		}
+	}
+}
+
+func ExampleAdjacencyList_MultiSourceBFS() {
+	// undirected path graph with sources 0 and 5, node 6 isolated
+	//   0--1--2--3--4--5   6
+	var g graph.Undirected
+	for n := graph.NI(0); n < 5; n++ {
+		g.AddEdge(n, n+1)
+	}
+	g.AdjacencyList = append(g.AdjacencyList, nil)
+	dist, nearest := g.MultiSourceBFS([]graph.NI{0, 5})
+	fmt.Println("dist:   ", dist)
+	fmt.Println("nearest:", nearest)
+	// Output:
+	// dist:    [0 1 2 2 1 0 -1]
+	// nearest: [0 0 0 5 5 5 -1]
+}
+
+func TestMultiSourceBFS(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(20)
+		g := make(graph.AdjacencyList, n)
+		for j := r.Intn(2 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.NI(r.Intn(n)))
+		}
+		sources := make([]graph.NI, r.Intn(4))
+		for j := range sources {
+			sources[j] = graph.NI(r.Intn(n))
+		}
+		dist, nearest := g.MultiSourceBFS(sources)
+		// compare with single source searches
+		for to := range g {
+			wd, wn := -1, graph.NI(-1)
+			for _, s := range sources {
+				p := g.BreadthFirstPath(s, graph.NI(to))
+				if p == nil && s == graph.NI(to) {
+					p = []graph.NI{s}
+				}
+				if p != nil && (wd < 0 || len(p)-1 < wd) {
+					wd, wn = len(p)-1, s
+				}
+			}
+			if dist[to] != wd || nearest[to] != wn {
+				t.Fatal(g, sources, "node", to, "got", dist[to], nearest[to],
+					"want", wd, wn)
+			}
+		}
+	}
+}
+
+func TestMultiSourceBFS_outOfRange(t *testing.T) {
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {},
+	}
+	dist, nearest := g.MultiSourceBFS([]graph.NI{-1, 2, 1})
+	if dist[0] != -1 || nearest[0] != -1 || dist[1] != 0 || nearest[1] != 1 {
+		t.Fatal("got", dist, nearest)
+	}
+}
+
+func ExampleAdjacencyList_Quotient() {
+	// arcs directed down, classes in parentheses
+	//   0 (0)
+	//  / \
+	// 1   2 (1)
+	// |   |
+	// 3   4 (2)
+	//  \ /
+	//   5 (2)
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {4},
+		3: {5},
+		4: {5},
+		5: {},
+	}
+	class := []int{0, 1, 1, 2, 2, 2}
+	classOf := func(n graph.NI) int { return class[n] }
+	fmt.Println(g.Quotient(classOf, true))
+	fmt.Println(g.Quotient(classOf, false))
+	// Output:
+	// [[1] [2] [2]] <nil>
+	// [[1] [2] []] <nil>
+}
+
+func ExampleAdjacencyList_Radius() {
+	//   0--1--2--3--4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	fmt.Println("diameter:", g.Diameter())
+	fmt.Println("radius:", g.Radius())
+	// Output:
+	// diameter: 4
+	// radius: 2
+}
+
+func ExampleAdjacencyList_RandomWalk() {
+	// arcs are directed right:
+	//   0-->1-->2-->3
+	//   ^   |
+	//   \---/
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {0, 2},
+		2: {3},
+		3: {},
+	}
+	src := rand.NewSource(1)
+	for i := 0; i < 3; i++ {
+		fmt.Println(g.RandomWalk(0, 6, src))
+	}
+	// Output:
+	// [0 1 2 3]
+	// [0 1 2 3]
+	// [0 1 0 1 0 1 2]
+}
+
+func ExampleAdjacencyList_ReachableWithinHops() {
+	// arcs are directed right:
+	//   0-->1-->2-->3-->4
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {4},
+		4: {},
+	}
+	for _, steps := range []int{0, 1, 3} {
+		fmt.Println(steps, "steps:", g.ReachableWithinHops(steps)[0].Slice())
+	}
+	// Output:
+	// 0 steps: [0]
+	// 1 steps: [0 1]
+	// 3 steps: [0 1 2 3]
+}
+
+func TestReachableWithinHops(t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	for i := 0; i < 100; i++ {
+		n := r.Intn(15)
+		g := make(graph.AdjacencyList, n)
+		if n > 0 {
+			for j := r.Intn(2 * n); j > 0; j-- {
+				fr := r.Intn(n)
+				g[fr] = append(g[fr], graph.NI(r.Intn(n)))
+			}
+		}
+		steps := r.Intn(n+2) - 1
+		got := g.ReachableWithinHops(steps)
+		for start := range g {
+			// breadth first search limited to steps levels
+			var want graph.Bits
+			if steps >= 0 {
+				want.SetBit(graph.NI(start), 1)
+			}
+			level := []graph.NI{graph.NI(start)}
+			for s := 0; s < steps; s++ {
+				var next []graph.NI
+				for _, fr := range level {
+					for _, to := range g[fr] {
+						if want.Bit(to) == 0 {
+							want.SetBit(to, 1)
+							next = append(next, to)
+						}
+					}
+				}
+				level = next
+			}
+			if !got[start].Eq(want) {
+				t.Fatal(g, "steps", steps, "start", start,
+					"got", got[start].Slice(), "want", want.Slice())
+			}
+		}
+	}
+}
+
+func ExampleLabeledAdjacencyList_ClosenessCentrality() {
+	//   (1)   (2)   (1)
+	// 0-----1-----2-----3   4
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}},
+		1: {{To: 0, Label: 1}, {To: 2, Label: 2}},
+		2: {{To: 1, Label: 2}, {To: 3, Label: 1}},
+		3: {{To: 2, Label: 1}},
+		4: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	for n, c := range g.ClosenessCentrality(w) {
+		fmt.Printf("%d %.3f\n", n, c)
+	}
+	// Output:
+	// 0 0.375
+	// 1 0.500
+	// 2 0.500
+	// 3 0.375
+	// 4 0.000
+}
+
 // A directed graph with negative arc weights.
 // Arc weights are encoded simply as label numbers.
 func ExampleLabeledAdjacencyList_FloydWarshall() {
@@ -74,6 +524,32 @@ func ExampleLabeledAdjacencyList_FloydWarshall() {
 	// [ 2  5  1  0]
 }
 
+func ExampleLabeledAdjacencyList_FloydWarshallPaths() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 2, Label: -1}},
+		1: {{To: 3, Label: -2}},
+		2: {{To: 1, Label: 4}, {To: 3, Label: 3}},
+		3: {{To: 0, Label: 2}},
+	}
+	d, p := g.FloydWarshallPaths(func(l graph.LI) float64 { return float64(l) })
+	for _, pi := range p {
+		fmt.Println(pi)
+	}
+	// recover path from 0 to 3
+	path := []graph.NI{3}
+	for n := graph.NI(3); n != 0; {
+		n = p[0][n]
+		path = append([]graph.NI{n}, path...)
+	}
+	fmt.Println("path 0 to 3:", path, "distance", d[0][3])
+	// Output:
+	// [-1 2 0 1]
+	// [3 -1 0 1]
+	// [3 2 -1 1]
+	// [3 2 0 -1]
+	// path 0 to 3: [0 2 1 3] distance 1
+}
+
 func ExampleLabeledDirected_FromListLabels() {
 	//      0
 	// 'A' / \ 'B'
@@ -222,6 +698,33 @@ func ExampleLabeledAdjacencyList_Unlabeled() {
 	// 2, []graph.NI{0, 1}
 }
 
+func ExampleLabeledAdjacencyList_SampleNeighbor() {
+	// labels are weights
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 0}, {To: 3, Label: 3}},
+		1: {{To: 0, Label: 0}},
+		2: {},
+		3: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	src := rand.NewSource(1)
+	count := map[graph.NI]int{}
+	for i := 0; i < 1000; i++ {
+		h, _ := g.SampleNeighbor(0, w, src)
+		count[h.To]++
+	}
+	fmt.Println("node 1 about 250:", count[1])
+	fmt.Println("node 2 never:", count[2])
+	fmt.Println("node 3 about 750:", count[3])
+	_, ok := g.SampleNeighbor(1, w, src)
+	fmt.Println("node 1 has positive weight arc:", ok)
+	// Output:
+	// node 1 about 250: 263
+	// node 2 never: 0
+	// node 3 about 750: 737
+	// node 1 has positive weight arc: false
+}
+
 func ExampleLabeledAdjacencyList_WeightedInDegree() {
 	//  0
 	//  | (weight = label: 3)
@@ -300,3 +803,50 @@ func ExampleLabeledAdjacencyList_WeightedOutDegree_undirected() {
 	// 1     7                    7
 	// 2     9                    9
 }
+
+func ExampleLabeledAdjacencyList_WeightedRandomWalk() {
+	// labels are weights.  arcs of weight 0 are never taken.
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 2}, {To: 2, Label: 1}},
+		1: {{To: 0, Label: 1}, {To: 3, Label: 0}},
+		2: {{To: 0, Label: 0}},
+		3: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	src := rand.NewSource(3)
+	for i := 0; i < 3; i++ {
+		fmt.Println(g.WeightedRandomWalk(0, 6, w, src))
+	}
+	// Output:
+	// [0 2]
+	// [0 1 0 2]
+	// [0 2]
+}
+
+func TestWeightedRandomWalk(t *testing.T) {
+	// star with arc weights 0 through 4 from the center
+	g := graph.LabeledAdjacencyList{
+		0: {{1, 0}, {2, 1}, {3, 2}, {4, 3}, {5, 4}},
+		1: {}, 2: {}, 3: {}, 4: {}, 5: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	src := rand.NewSource(1)
+	count := make([]int, len(g))
+	const n = 10000
+	for i := 0; i < n; i++ {
+		walk := g.WeightedRandomWalk(0, 5, w, src)
+		if len(walk) != 2 {
+			t.Fatal("walk", walk)
+		}
+		count[walk[1]]++
+	}
+	if count[1] != 0 {
+		t.Fatal("zero weight arc taken", count[1], "times")
+	}
+	for to := 2; to <= 5; to++ {
+		want := float64(n) * float64(to-1) / 10
+		if f := float64(count[to]); f < want*.9 || f > want*1.1 {
+			t.Fatal("node", to, "reached", count[to], "times, want about", want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+// Package adjmat reads and writes graphs from package graph as adjacency
+// matrices in CSV format.
+//
+// Row i of the matrix gives arcs from node i.  The entry in column j is
+// the number of arcs from node i to node j, or for weighted graphs the
+// weight of the arc, with 0 meaning no arc.  The matrix must be square.
+// There is no header row.
+//
+// Like package dot, adjmat is a separate package from graph.  Graph knows
+// nothing of CSV.
+package adjmat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadAdjacencyList reads a CSV matrix of non-negative integer arc counts.
+//
+// An entry of 1 gives an arc, larger values give parallel arcs.  The order
+// of the result is the matrix size.
+func ReadAdjacencyList(r io.Reader) (graph.AdjacencyList, error) {
+	m, err := readSquare(r)
+	if err != nil {
+		return nil, err
+	}
+	g := make(graph.AdjacencyList, len(m))
+	for fr, row := range m {
+		for to, s := range row {
+			c, err := strconv.Atoi(s)
+			if err != nil || c < 0 {
+				return nil, fmt.Errorf("adjmat: row %d column %d: "+
+					"invalid arc count %q", fr, to, s)
+			}
+			for ; c > 0; c-- {
+				g[fr] = append(g[fr], graph.NI(to))
+			}
+		}
+	}
+	return g, nil
+}
+
+// ReadWeighted reads a CSV matrix of numeric arc weights.
+//
+// Each non-zero entry gives an arc.  Arcs are labeled with indexes into
+// the returned weights, in row major order.  A WeightFunc for the result is
+//
+//	func(l graph.LI) float64 { return weights[l] }
+func ReadWeighted(r io.Reader) (g graph.LabeledAdjacencyList, weights []float64, err error) {
+	m, err := readSquare(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	g = make(graph.LabeledAdjacencyList, len(m))
+	for fr, row := range m {
+		for to, s := range row {
+			w, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("adjmat: row %d column %d: "+
+					"invalid weight %q", fr, to, s)
+			}
+			if w != 0 {
+				g[fr] = append(g[fr],
+					graph.Half{To: graph.NI(to), Label: graph.LI(len(weights))})
+				weights = append(weights, w)
+			}
+		}
+	}
+	return g, weights, nil
+}
+
+// readSquare reads CSV records, checking that they form a square matrix.
+func readSquare(r io.Reader) ([][]string, error) {
+	c := csv.NewReader(r)
+	c.FieldsPerRecord = -1 // checked below for a clearer error
+	c.TrimLeadingSpace = true
+	m, err := c.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("adjmat: %v", err)
+	}
+	for i, row := range m {
+		if len(row) != len(m) {
+			return nil, fmt.Errorf("adjmat: matrix not square: "+
+				"%d rows, row %d has %d columns", len(m), i, len(row))
+		}
+	}
+	return m, nil
+}
+
+// WriteAdjacencyList writes g as a CSV matrix of arc counts.
+func WriteAdjacencyList(g graph.AdjacencyList, w io.Writer) error {
+	c := csv.NewWriter(w)
+	counts := make([]int, len(g))
+	row := make([]string, len(g))
+	for _, to := range g {
+		for i := range counts {
+			counts[i] = 0
+		}
+		for _, to := range to {
+			counts[to]++
+		}
+		for i, n := range counts {
+			row[i] = strconv.Itoa(n)
+		}
+		if err := c.Write(row); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WriteWeighted writes g as a CSV matrix of arc weights given by
+// weight function wf.
+//
+// Absent arcs are written as 0.  WriteWeighted returns an error if g has
+// parallel arcs, or an arc of weight 0, as these cannot be represented.
+func WriteWeighted(g graph.LabeledAdjacencyList, wf graph.WeightFunc, w io.Writer) error {
+	c := csv.NewWriter(w)
+	row := make([]string, len(g))
+	for fr, to := range g {
+		for i := range row {
+			row[i] = ""
+		}
+		for _, h := range to {
+			if row[h.To] != "" {
+				return fmt.Errorf("adjmat: parallel arcs %d->%d", fr, h.To)
+			}
+			wt := wf(h.Label)
+			if wt == 0 {
+				return fmt.Errorf("adjmat: arc %d->%d has weight 0", fr, h.To)
+			}
+			row[h.To] = strconv.FormatFloat(wt, 'g', -1, 64)
+		}
+		for i, s := range row {
+			if s == "" {
+				row[i] = "0"
+			}
+		}
+		if err := c.Write(row); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
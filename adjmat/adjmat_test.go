@@ -0,0 +1,173 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package adjmat_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/adjmat"
+)
+
+func ExampleReadAdjacencyList() {
+	g, err := adjmat.ReadAdjacencyList(strings.NewReader(`0,1,1
+0,0,1
+0,0,0
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 2]
+	// 1 [2]
+	// 2 []
+}
+
+func ExampleReadAdjacencyList_notSquare() {
+	_, err := adjmat.ReadAdjacencyList(strings.NewReader(`0,1,1
+0,0,1
+`))
+	fmt.Println(err)
+	// Output:
+	// adjmat: matrix not square: 2 rows, row 0 has 3 columns
+}
+
+func ExampleReadWeighted() {
+	g, weights, err := adjmat.ReadWeighted(strings.NewReader(`0, 2.5, 0
+0, 0, 4
+1, 0, 0
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	fmt.Println(weights)
+	// Output:
+	// 0 [{1 0}]
+	// 1 [{2 1}]
+	// 2 [{0 2}]
+	// [2.5 4 1]
+}
+
+func ExampleWriteAdjacencyList() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2, 2},
+		2: {},
+	}
+	adjmat.WriteAdjacencyList(g, os.Stdout)
+	// Output:
+	// 0,1,1
+	// 0,0,2
+	// 0,0,0
+}
+
+func ExampleWriteWeighted() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}},
+		1: {{To: 2, Label: 1}},
+		2: {{To: 0, Label: 2}},
+	}
+	weights := []float64{2.5, 4, 1}
+	adjmat.WriteWeighted(g, func(l graph.LI) float64 { return weights[l] },
+		os.Stdout)
+	// Output:
+	// 0,2.5,0
+	// 0,0,4
+	// 1,0,0
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, s := range []string{
+		"0,1\n",
+		"0,1\n1\n",
+		"0,x\n0,0\n",
+		"0,-1\n0,0\n",
+		"0,1.5\n0,0\n",
+	} {
+		if g, err := adjmat.ReadAdjacencyList(strings.NewReader(s)); err == nil {
+			t.Fatalf("%q accepted as %v", s, g)
+		}
+	}
+	if g, _, err := adjmat.ReadWeighted(strings.NewReader("0,x\n0,0\n")); err == nil {
+		t.Fatal("accepted", g)
+	}
+}
+
+func TestWriteWeightedErrors(t *testing.T) {
+	w := func(l graph.LI) float64 { return float64(l) }
+	var b bytes.Buffer
+	g := graph.LabeledAdjacencyList{{{To: 0, Label: 1}, {To: 0, Label: 2}}}
+	if err := adjmat.WriteWeighted(g, w, &b); err == nil {
+		t.Fatal("parallel arcs accepted")
+	}
+	g = graph.LabeledAdjacencyList{{{To: 0, Label: 0}}}
+	if err := adjmat.WriteWeighted(g, w, &b); err == nil {
+		t.Fatal("weight 0 accepted")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for i := 0; i < 50; i++ {
+		n := r.Intn(12)
+		g := make(graph.AdjacencyList, n)
+		var m [][]float64
+		for fr := range g {
+			row := make([]float64, n)
+			for to := range row {
+				if r.Intn(3) == 0 {
+					g[fr] = append(g[fr], graph.NI(to))
+					row[to] = float64(r.Intn(1000)+1) / 8
+				}
+			}
+			m = append(m, row)
+		}
+		var b bytes.Buffer
+		if err := adjmat.WriteAdjacencyList(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		h, err := adjmat.ReadAdjacencyList(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(h) != n || !g.Equal(h) {
+			t.Fatal(g, h)
+		}
+		// weighted
+		lg := make(graph.LabeledAdjacencyList, n)
+		var weights []float64
+		for fr, to := range g {
+			for _, to := range to {
+				lg[fr] = append(lg[fr], graph.Half{to, graph.LI(len(weights))})
+				weights = append(weights, m[fr][to])
+			}
+		}
+		b.Reset()
+		wf := func(l graph.LI) float64 { return weights[l] }
+		if err := adjmat.WriteWeighted(lg, wf, &b); err != nil {
+			t.Fatal(err)
+		}
+		lh, hw, err := adjmat.ReadWeighted(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lh) != n || !lg.Equal(lh) ||
+			fmt.Sprint(hw) != fmt.Sprint(weights) {
+			t.Fatal(lg, weights, lh, hw)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2016 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+// Package bf provides a parameterized breadth-first search.
+//
+// A single variadic function, Search, takes options in the form of configuration functions.
+// Options are similar to those of package df.
+package bf
+
+import (
+	"errors"
+
+	"github.com/soniakeys/graph"
+)
+
+// Search performs a breadth-first search or traversal of graph g starting at
+// node start.
+//
+// Nodes are visited in order of level, the number of arcs from start.  Within
+// a level, nodes are visited in the order they were reached.  Arcs from each
+// visited node are visited in slice order, or random order if Rand is
+// specified, as the node is visited.
+//
+// Options controlling the search are specified with configuration functions
+// defined in this package.
+//
+// A non-nil error indicates some problem initializing the search, such as
+// an invalid graph type or options.
+func Search(g interface{}, start graph.NI, options ...func(*config)) error {
+	cf := &config{}
+	for _, o := range options {
+		o(cf)
+	}
+	if cf.nodeVisitor != nil && cf.okNodeVisitor != nil {
+		return errors.New("NodeVisitor and OkNodeVisitor cannot both be specified")
+	}
+	if cf.arcVisitor != nil && cf.okArcVisitor != nil {
+		return errors.New("ArcVisitor and OkArcVisitor cannot both be specified")
+	}
+	if cf.visited == nil {
+		cf.visited = &graph.Bits{}
+	}
+	switch t := g.(type) {
+	case graph.AdjacencyList:
+		cf.search(start, func(n graph.NI) int { return len(t[n]) },
+			func(n graph.NI, x int) graph.NI { return t[n][x] })
+	case graph.LabeledAdjacencyList:
+		cf.search(start, func(n graph.NI) int { return len(t[n]) },
+			func(n graph.NI, x int) graph.NI { return t[n][x].To })
+	default:
+		return errors.New("invalid graph type")
+	}
+	return nil
+}
+
+// search does the traversal.  Function deg returns the number of arcs from
+// a node, and function to returns the node at the end of arc x from node n.
+func (cf *config) search(start graph.NI, deg func(graph.NI) int, to func(n graph.NI, x int) graph.NI) {
+	b := cf.visited
+	if b.Bit(start) != 0 {
+		return
+	}
+	b.SetBit(start, 1)
+	level := []graph.NI{start}
+	var next []graph.NI
+	var xs []int // arc order
+	for l := 0; len(level) > 0; l++ {
+		for _, n := range level {
+			if v := cf.nodeVisitor; v != nil {
+				v(n)
+			}
+			if v := cf.okNodeVisitor; v != nil && !v(n) {
+				return
+			}
+			if v := cf.levelVisitor; v != nil {
+				v(n, l)
+			}
+			if r := cf.rand; r != nil {
+				xs = r.Perm(deg(n))
+			} else {
+				xs = xs[:0]
+				for x, d := 0, deg(n); x < d; x++ {
+					xs = append(xs, x)
+				}
+			}
+			for _, x := range xs {
+				if v := cf.arcVisitor; v != nil {
+					v(n, x)
+				}
+				if v := cf.okArcVisitor; v != nil && !v(n, x) {
+					return
+				}
+				if t := to(n, x); b.Bit(t) == 0 {
+					b.SetBit(t, 1)
+					next = append(next, t)
+				}
+			}
+		}
+		level, next = next, level[:0]
+	}
+}
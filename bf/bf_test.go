@@ -0,0 +1,156 @@
+// Copyright 2016 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package bf_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/bf"
+)
+
+func ExampleNodeVisitor() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {1},
+	}
+	bf.Search(g, 0, bf.NodeVisitor(func(n graph.NI) {
+		fmt.Println("visit", n)
+	}))
+	// Output:
+	// visit 0
+	// visit 1
+	// visit 2
+	// visit 3
+}
+
+func ExampleOkNodeVisitor_earlyTermination() {
+	//   0-->3
+	//  / \
+	// 1-->2
+	g := graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {2},
+		3: {},
+	}
+	var found bool
+	bf.Search(g, 0, bf.OkNodeVisitor(func(n graph.NI) bool {
+		fmt.Println("visit", n)
+		found = n == 2
+		return !found
+	}))
+	fmt.Println("found =", found)
+	// Output:
+	// visit 0
+	// visit 1
+	// visit 2
+	// found = true
+}
+
+func ExampleLevelVisitor() {
+	//   0
+	//  / \
+	// 1   2
+	//  \ / \
+	//   3   4
+	//       |
+	//       5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		1: {{To: 3}},
+		2: {{To: 3}, {To: 4}},
+		4: {{To: 5}},
+		5: {},
+	}
+	bf.Search(g, 0, bf.LevelVisitor(func(n graph.NI, l int) {
+		fmt.Println("node", n, "level", l)
+	}))
+	// Output:
+	// node 0 level 0
+	// node 1 level 1
+	// node 2 level 1
+	// node 3 level 2
+	// node 4 level 2
+	// node 5 level 3
+}
+
+func ExampleArcVisitor() {
+	//   0
+	//  / \
+	// 1-->2
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {},
+	}
+	bf.Search(g, 0, bf.ArcVisitor(func(n graph.NI, x int) {
+		fmt.Println("arc", n, "->", g[n][x])
+	}))
+	// Output:
+	// arc 0 -> 1
+	// arc 0 -> 2
+	// arc 1 -> 2
+}
+
+var k10 graph.Directed
+
+func init() {
+	r := rand.New(rand.NewSource(11))
+	k10, _ = graph.KroneckerDirected(10, 10, r)
+}
+
+func TestK10(t *testing.T) {
+	// bf and df must reach the same nodes
+	var want, got, gotRand graph.Bits
+	k10.DepthFirst(0, &want, nil)
+	bf.Search(k10.AdjacencyList, 0, bf.Visited(&got))
+	if !got.Eq(want) {
+		t.Fatal("bf reached", got.PopCount(), "want", want.PopCount())
+	}
+	bf.Search(k10.AdjacencyList, 0, bf.Visited(&gotRand),
+		bf.Rand(rand.New(rand.NewSource(1))))
+	if !gotRand.Eq(want) {
+		t.Fatal("bf Rand reached", gotRand.PopCount(), "want", want.PopCount())
+	}
+}
+
+func TestLevels(t *testing.T) {
+	// levels must match path lengths of BreadthFirst
+	var f graph.FromList
+	k10.BreadthFirst(0, nil, &f, func(graph.NI) bool { return true })
+	bf.Search(k10.AdjacencyList, 0, bf.LevelVisitor(func(n graph.NI, l int) {
+		if f.Paths[n].Len != l+1 {
+			t.Fatal("node", n, "level", l, "path len", f.Paths[n].Len)
+		}
+	}))
+}
+
+func TestSearchErrors(t *testing.T) {
+	g := graph.AdjacencyList{}
+	nv := func(graph.NI) {}
+	okv := func(graph.NI) bool { return true }
+	if bf.Search(g, 0, bf.NodeVisitor(nv), bf.OkNodeVisitor(okv)) == nil {
+		t.Fatal("expected error for NodeVisitor and OkNodeVisitor")
+	}
+	if bf.Search(graph.Directed{}, 0) == nil {
+		t.Fatal("expected error for invalid graph type")
+	}
+}
+
+func BenchmarkBFA(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var bm graph.Bits
+		bf.Search(k10.AdjacencyList, 0, bf.Visited(&bm))
+	}
+}
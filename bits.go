@@ -13,11 +13,20 @@ import (
 //
 // The current implementation is backed by a big.Int and so is a reference
 // type in the same way a big.Int is.
+//
+// Set operations follow the big.Int convention of setting the receiver
+// from two operands: union is Or, intersection is And, and difference is
+// AndNot.  The receiver may be one of the operands to update a set in place,
+// as in z.Or(z, x), or a separate value to leave the operands unchanged.
+// PopCount gives the number of elements of a set.
 type Bits struct {
 	i big.Int
 }
 
 // NewBits constructs a Bits value with the bits ns set to 1.
+//
+// For nodes in a slice s, call NewBits(s...).  To set the bits of s in an
+// existing value z, use z.Or(z, NewBits(s...)).
 func NewBits(ns ...NI) (b Bits) {
 	for _, n := range ns {
 		b.SetBit(n, 1)
@@ -106,6 +115,8 @@ func (b Bits) From(n NI) NI {
 // It stops if v returns false.
 //
 // Iterate returns true normally.  It returns false if v returns false.
+// It does not allocate and so is preferred over ranging over the result
+// of Slice in performance-sensitive code.
 //
 // Bit values should not be modified during iteration, by the visitor function
 // for example.  See From for an iteration method that allows modification.
@@ -183,6 +194,8 @@ func (b Bits) Single() bool {
 }
 
 // Slice returns a slice with the positions of each 1 bit.
+//
+// To visit 1 bits without allocating a slice, use Iterate or From.
 func (b Bits) Slice() (s []NI) {
 	// (alternative implementation might use Popcount and make to get the
 	// exact cap slice up front.  unclear if that would be better.)
@@ -9,6 +9,25 @@ import (
 	"github.com/soniakeys/graph"
 )
 
+func ExampleBits() {
+	x := graph.NewBits(1, 2, 3)
+	y := graph.NewBits(3, 4)
+	var u, i, d graph.Bits
+	u.Or(x, y)     // union
+	i.And(x, y)    // intersection
+	d.AndNot(x, y) // difference
+	fmt.Println("union:       ", u.Slice())
+	fmt.Println("intersection:", i.Slice())
+	fmt.Println("difference:  ", d.Slice(), d.PopCount())
+	// operands are unchanged
+	fmt.Println(x.Slice(), y.Slice())
+	// Output:
+	// union:        [1 2 3 4]
+	// intersection: [3]
+	// difference:   [1 2] 2
+	// [1 2 3] [3 4]
+}
+
 func ExampleNewBits() {
 	x := graph.NewBits(3, 5)
 	fmt.Println(x.Slice())
@@ -16,6 +35,19 @@ func ExampleNewBits() {
 	// [3 5]
 }
 
+func ExampleNewBits_slice() {
+	sources := []graph.NI{4, 1}
+	x := graph.NewBits(sources...)
+	fmt.Println(x.Slice())
+	// add more nodes to x
+	more := []graph.NI{2, 4}
+	x.Or(x, graph.NewBits(more...))
+	fmt.Println(x.Slice())
+	// Output:
+	// [1 4]
+	// [1 2 4]
+}
+
 func ExampleBits_And() {
 	x := graph.NewBits(3, 5, 6)
 	y := graph.NewBits(4, 5, 6)
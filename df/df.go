@@ -8,7 +8,7 @@ package df
 
 import (
 	"errors"
-	"math/rand"
+	"sort"
 
 	"github.com/soniakeys/graph"
 )
@@ -32,6 +32,9 @@ func Search(g interface{}, start graph.NI, options ...func(*config)) error {
 	if cf.arcVisitor != nil && cf.okArcVisitor != nil {
 		return errors.New("ArcVisitor and OkArcVisitor cannot both be specified")
 	}
+	if cf.rand != nil && cf.sorted {
+		return errors.New("Rand and Sorted cannot both be specified")
+	}
 	if cf.visited == nil { // for now, visited required internally
 		cf.visited = &graph.Bits{}
 	}
@@ -129,17 +132,17 @@ func (cf *config) composeSearchVisitor(f func(graph.NI) bool) func(graph.NI) boo
 }
 
 func (cf *config) adjRecurseSearch(g graph.AdjacencyList, search func(graph.NI) bool) func(graph.NI) bool {
-	if r := cf.rand; r != nil {
-		return cf.adjRandSearch(g, search, r)
+	if p := cf.adjPermFunc(g); p != nil {
+		return cf.adjPermSearch(g, search, p)
 	}
 	return cf.adjToSearch(g, search)
 }
 
-func (cf *config) adjRandSearch(g graph.AdjacencyList, search func(graph.NI) bool, r *rand.Rand) func(graph.NI) bool {
+func (cf *config) adjPermSearch(g graph.AdjacencyList, search func(graph.NI) bool, perm func(graph.NI) []int) func(graph.NI) bool {
 	if v := cf.okArcVisitor; v != nil {
 		return func(n graph.NI) bool {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				if !v(n, x) || !search(to[x]) {
 					return false
 				}
@@ -150,7 +153,7 @@ func (cf *config) adjRandSearch(g graph.AdjacencyList, search func(graph.NI) boo
 	if v := cf.arcVisitor; v != nil {
 		return func(n graph.NI) bool {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				v(n, x)
 				if !search(to[x]) {
 					return false
@@ -161,7 +164,7 @@ func (cf *config) adjRandSearch(g graph.AdjacencyList, search func(graph.NI) boo
 	}
 	return func(n graph.NI) bool {
 		to := g[n]
-		for _, i := range r.Perm(len(to)) {
+		for _, i := range perm(n) {
 			if !search(to[i]) {
 				return false
 			}
@@ -203,17 +206,17 @@ func (cf *config) adjToSearch(g graph.AdjacencyList, search func(graph.NI) bool)
 }
 
 func (cf *config) adjRecurseTraverse(g graph.AdjacencyList, traverse func(graph.NI)) func(graph.NI) {
-	if r := cf.rand; r != nil {
-		return cf.adjRandTraverse(g, traverse, r)
+	if p := cf.adjPermFunc(g); p != nil {
+		return cf.adjPermTraverse(g, traverse, p)
 	}
 	return cf.adjToTraverse(g, traverse)
 }
 
-func (cf *config) adjRandTraverse(g graph.AdjacencyList, traverse func(graph.NI), r *rand.Rand) func(graph.NI) {
+func (cf *config) adjPermTraverse(g graph.AdjacencyList, traverse func(graph.NI), perm func(graph.NI) []int) func(graph.NI) {
 	if v := cf.arcVisitor; v != nil {
 		return func(n graph.NI) {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				v(n, x)
 				traverse(to[x])
 			}
@@ -221,7 +224,7 @@ func (cf *config) adjRandTraverse(g graph.AdjacencyList, traverse func(graph.NI)
 	}
 	return func(n graph.NI) {
 		to := g[n]
-		for _, x := range r.Perm(len(to)) {
+		for _, x := range perm(n) {
 			traverse(to[x])
 		}
 	}
@@ -243,6 +246,21 @@ func (cf *config) adjToTraverse(g graph.AdjacencyList, traverse func(graph.NI))
 	}
 }
 
+// adjPermFunc returns a function giving the order to visit arcs from a node,
+// or nil if arcs are to be visited in slice order.
+func (cf *config) adjPermFunc(g graph.AdjacencyList) func(graph.NI) []int {
+	if r := cf.rand; r != nil {
+		return func(n graph.NI) []int { return r.Perm(len(g[n])) }
+	}
+	if cf.sorted {
+		return func(n graph.NI) []int {
+			to := g[n]
+			return sortedArcs(len(to), func(x int) graph.NI { return to[x] })
+		}
+	}
+	return nil
+}
+
 func (cf *config) labFunc(g graph.LabeledAdjacencyList) func(graph.NI) {
 	if cf.okNodeVisitor == nil && cf.okArcVisitor == nil {
 		f := dfTraverseNodes{visited: cf.visitedFunc()}
@@ -256,18 +274,31 @@ func (cf *config) labFunc(g graph.LabeledAdjacencyList) func(graph.NI) {
 	return func(start graph.NI) { search(start) }
 }
 
-func (cf *config) labRecurseSearch(g graph.LabeledAdjacencyList, search func(graph.NI) bool) func(graph.NI) bool {
+func (cf *config) labPermFunc(g graph.LabeledAdjacencyList) func(graph.NI) []int {
 	if r := cf.rand; r != nil {
-		return cf.labRandSearch(g, search, r)
+		return func(n graph.NI) []int { return r.Perm(len(g[n])) }
+	}
+	if cf.sorted {
+		return func(n graph.NI) []int {
+			to := g[n]
+			return sortedArcs(len(to), func(x int) graph.NI { return to[x].To })
+		}
+	}
+	return nil
+}
+
+func (cf *config) labRecurseSearch(g graph.LabeledAdjacencyList, search func(graph.NI) bool) func(graph.NI) bool {
+	if p := cf.labPermFunc(g); p != nil {
+		return cf.labPermSearch(g, search, p)
 	}
 	return cf.labToSearch(g, search)
 }
 
-func (cf *config) labRandSearch(g graph.LabeledAdjacencyList, search func(graph.NI) bool, r *rand.Rand) func(graph.NI) bool {
+func (cf *config) labPermSearch(g graph.LabeledAdjacencyList, search func(graph.NI) bool, perm func(graph.NI) []int) func(graph.NI) bool {
 	if v := cf.okArcVisitor; v != nil {
 		return func(n graph.NI) bool {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				if !v(n, x) || !search(to[x].To) {
 					return false
 				}
@@ -278,7 +309,7 @@ func (cf *config) labRandSearch(g graph.LabeledAdjacencyList, search func(graph.
 	if v := cf.arcVisitor; v != nil {
 		return func(n graph.NI) bool {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				v(n, x)
 				if !search(to[x].To) {
 					return false
@@ -289,7 +320,7 @@ func (cf *config) labRandSearch(g graph.LabeledAdjacencyList, search func(graph.
 	}
 	return func(n graph.NI) bool {
 		to := g[n]
-		for _, i := range r.Perm(len(to)) {
+		for _, i := range perm(n) {
 			if !search(to[i].To) {
 				return false
 			}
@@ -331,17 +362,17 @@ func (cf *config) labToSearch(g graph.LabeledAdjacencyList, search func(graph.NI
 }
 
 func (cf *config) labRecurseTraverse(g graph.LabeledAdjacencyList, traverse func(graph.NI)) func(graph.NI) {
-	if r := cf.rand; r != nil {
-		return cf.labRandTraverse(g, traverse, r)
+	if p := cf.labPermFunc(g); p != nil {
+		return cf.labPermTraverse(g, traverse, p)
 	}
 	return cf.labToTraverse(g, traverse)
 }
 
-func (cf *config) labRandTraverse(g graph.LabeledAdjacencyList, traverse func(graph.NI), r *rand.Rand) func(graph.NI) {
+func (cf *config) labPermTraverse(g graph.LabeledAdjacencyList, traverse func(graph.NI), perm func(graph.NI) []int) func(graph.NI) {
 	if v := cf.arcVisitor; v != nil {
 		return func(n graph.NI) {
 			to := g[n]
-			for _, x := range r.Perm(len(to)) {
+			for _, x := range perm(n) {
 				v(n, x)
 				traverse(to[x].To)
 			}
@@ -349,7 +380,7 @@ func (cf *config) labRandTraverse(g graph.LabeledAdjacencyList, traverse func(gr
 	}
 	return func(n graph.NI) {
 		to := g[n]
-		for _, i := range r.Perm(len(to)) {
+		for _, i := range perm(n) {
 			traverse(to[i].To)
 		}
 	}
@@ -370,3 +401,23 @@ func (cf *config) labToTraverse(g graph.LabeledAdjacencyList, traverse func(grap
 		}
 	}
 }
+
+// sortedArcs returns arc indexes 0..n-1 ordered by ascending to node.
+// Parallel arcs remain in slice order.
+func sortedArcs(n int, to func(x int) graph.NI) []int {
+	s := arcSorter{make([]int, n), to}
+	for x := range s.x {
+		s.x[x] = x
+	}
+	sort.Stable(s)
+	return s.x
+}
+
+type arcSorter struct {
+	x  []int
+	to func(int) graph.NI
+}
+
+func (s arcSorter) Len() int           { return len(s.x) }
+func (s arcSorter) Less(i, j int) bool { return s.to(s.x[i]) < s.to(s.x[j]) }
+func (s arcSorter) Swap(i, j int)      { s.x[i], s.x[j] = s.x[j], s.x[i] }
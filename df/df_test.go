@@ -113,6 +113,36 @@ func ExampleOkArcVisitor_cyclic() {
 }
 */
 
+func ExampleSorted() {
+	//   0
+	//  / \
+	// 2   1
+	//  \ /
+	//   3
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 2}, {To: 1}},
+		1: {{To: 3}},
+		2: {{To: 3}},
+		3: {},
+	}
+	df.Search(g, 0, df.Sorted(true), df.NodeVisitor(func(n graph.NI) {
+		fmt.Println("visit", n)
+	}))
+	// Output:
+	// visit 0
+	// visit 1
+	// visit 3
+	// visit 2
+}
+
+func TestSortedRand(t *testing.T) {
+	err := df.Search(k10.AdjacencyList, 0,
+		df.Sorted(true), df.Rand(rand.New(rand.NewSource(1))))
+	if err == nil {
+		t.Fatal("expected error for Sorted and Rand")
+	}
+}
+
 var k10 graph.Directed
 
 func init() {
@@ -17,6 +17,7 @@ type config struct {
 	okNodeVisitor graph.OkNodeVisitor
 	pathBits      *graph.Bits
 	rand          *rand.Rand
+	sorted        bool
 	visited       *graph.Bits
 }
 
@@ -79,10 +80,20 @@ func PathBits(b *graph.Bits) func(*config) {
 }
 
 // Rand specifies to traverse edges from each visited node in random order.
+//
+// Rand and Sorted cannot both be specified.
 func Rand(r *rand.Rand) func(*config) {
 	return func(c *config) { c.rand = r }
 }
 
+// Sorted specifies to traverse edges from each visited node in order of
+// ascending to-node.
+//
+// The graph is not modified.  Sorted and Rand cannot both be specified.
+func Sorted(s bool) func(*config) {
+	return func(c *config) { c.sorted = s }
+}
+
 // Visited specifies a graph.Bits value to record visited nodes.
 //
 // For each node visited, the corresponding bit is set to 1.  Other bits
@@ -4,12 +4,15 @@
 package graph
 
 // dir.go has methods specific to directed graphs, types Directed and
-// LabeledDirected, also Dominators.
+// LabeledDirected, also TopoOrder and Dominators.
 //
 // Methods on Directed are first, with exported methods alphabetized.
-// Dominators type and methods are at the end.
+// TopoOrder and Dominators types and methods are at the end.
 
-import "errors"
+import (
+	"errors"
+	"sort"
+)
 
 // DAGMaxLenPath finds a maximum length path in a directed acyclic graph.
 //
@@ -44,6 +47,110 @@ func (g Directed) DAGMaxLenPath(ordering []NI) (path []NI) {
 	return append([]NI{n}, path...)
 }
 
+// ElementaryCycles finds all elementary cycles of g using Johnson's
+// algorithm.
+//
+// An elementary cycle, or elementary circuit, is a cycle in which no node
+// appears twice.  Each cycle is returned as a node sequence starting with
+// the least node of the cycle.  The start node is not repeated at the end.
+// A loop is returned as a single node.  Cycles are distinguished by node
+// sequence so parallel arcs do not produce duplicate cycles.
+//
+// The number of elementary cycles can be exponential in the number of nodes.
+// Time is proportional to (n+m)(n+c) for a graph of n nodes, m arcs, and
+// c cycles.
+func (g Directed) ElementaryCycles() (cycles [][]NI) {
+	a := g.AdjacencyList
+	t, _ := g.Transpose()
+	var scc, blocked, seen Bits
+	bl := make([][]NI, len(a)) // Johnson's B lists
+	adj := make([][]NI, len(a))
+	var stack []NI
+	// reach sets bits of r for nodes >= s reachable from s in x.
+	reach := func(x AdjacencyList, s NI, r *Bits) {
+		r.Clear()
+		r.SetBit(s, 1)
+		q := []NI{s}
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, to := range x[n] {
+				if to >= s && r.Bit(to) == 0 {
+					r.SetBit(to, 1)
+					q = append(q, to)
+				}
+			}
+		}
+	}
+	var unblock func(NI)
+	unblock = func(n NI) {
+		blocked.SetBit(n, 0)
+		b := bl[n]
+		bl[n] = nil
+		for _, w := range b {
+			if blocked.Bit(w) == 1 {
+				unblock(w)
+			}
+		}
+	}
+	var s NI
+	var circuit func(NI) bool
+	circuit = func(v NI) (f bool) {
+		stack = append(stack, v)
+		blocked.SetBit(v, 1)
+		for _, w := range adj[v] {
+			switch {
+			case w == s:
+				cycles = append(cycles, append([]NI{}, stack...))
+				f = true
+			case blocked.Bit(w) == 0:
+				if circuit(w) {
+					f = true
+				}
+			}
+		}
+		if f {
+			unblock(v)
+		} else {
+		next:
+			for _, w := range adj[v] {
+				for _, x := range bl[w] {
+					if x == v {
+						continue next
+					}
+				}
+				bl[w] = append(bl[w], v)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		return
+	}
+	for s = 0; int(s) < len(a); s++ {
+		// strongly connected component of s in the subgraph induced by
+		// nodes >= s
+		var back Bits
+		reach(a, s, &scc)
+		reach(t.AdjacencyList, s, &back)
+		scc.And(scc, back)
+		// distinct arcs within the component
+		scc.Iterate(func(n NI) bool {
+			seen.Clear()
+			adj[n] = adj[n][:0]
+			for _, to := range a[n] {
+				if scc.Bit(to) == 1 && seen.Bit(to) == 0 {
+					seen.SetBit(to, 1)
+					adj[n] = append(adj[n], to)
+				}
+			}
+			blocked.SetBit(n, 0)
+			bl[n] = nil
+			return true
+		})
+		circuit(s)
+	}
+	return
+}
+
 // EulerianCycle finds an Eulerian cycle in a directed multigraph.
 //
 // * If g has no nodes, result is nil, nil.
@@ -187,14 +294,17 @@ func (e *eulerian) pushUndir() {
 		e.s++
 		e.p[e.s] = w
 		e.g[u] = arcs[1:] // consume arc
-		// here is the only difference, consume reciprocal arc as well:
-		a2 := e.g[w]
-		for x, rx := range a2 {
-			if rx == u { // here it is
-				last := len(a2) - 1
-				a2[x] = a2[last]   // someone else gets the seat
-				e.g[w] = a2[:last] // and it's gone.
-				break
+		// here is the only difference, consume reciprocal arc as well.
+		// a loop is a single arc and has no reciprocal.
+		if w != u {
+			a2 := e.g[w]
+			for x, rx := range a2 {
+				if rx == u { // here it is
+					last := len(a2) - 1
+					a2[x] = a2[last]   // someone else gets the seat
+					e.g[w] = a2[:last] // and it's gone.
+					break
+				}
 			}
 		}
 		u = w
@@ -238,6 +348,161 @@ func newEulerian(g AdjacencyList, m int) *eulerian {
 	return e
 }
 
+// FeedbackArcSet finds a set of arcs whose removal makes g acyclic.
+//
+// The method is the greedy heuristic of Eades, Lin, and Smyth.  It builds
+// an ordering of nodes by repeatedly removing sinks to the end of the
+// ordering, sources to the start, and otherwise the node with the greatest
+// difference of out-degree minus in-degree to the start.  Result arcs are
+// those that go backward in the ordering, and loops.  For a connected
+// simple graph of n nodes and m arcs with no reciprocal arcs, at most
+// m/2 - n/6 arcs are returned.  The result is not necessarily minimum.
+//
+// Nodes are kept in buckets by degree difference, with queues of pending
+// sinks and sources, so time is O(n+m).
+//
+// Each result Edge has N1 as the from node and N2 as the to node of an arc.
+// Parallel arcs are listed once for each arc.  The result is nil for an
+// acyclic graph.
+func (g Directed) FeedbackArcSet() (arcs []Edge) {
+	a := g.AdjacencyList
+	t, _ := g.Transpose()
+	in := make([]int, len(a))
+	out := make([]int, len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			if to != NI(fr) {
+				out[fr]++
+				in[to]++
+			}
+		}
+	}
+	// buckets are doubly linked lists of nodes that are neither sinks
+	// nor sources, indexed by out-degree minus in-degree plus offset.
+	maxIn, maxOut := 0, 0
+	for n := range a {
+		if in[n] > maxIn {
+			maxIn = in[n]
+		}
+		if out[n] > maxOut {
+			maxOut = out[n]
+		}
+	}
+	offset := maxIn
+	head := make([]NI, maxIn+maxOut+1)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]NI, len(a))
+	next := make([]NI, len(a))
+	bucket := make([]int, len(a)) // bucket of each node, or -1
+	top := 0                      // no non-empty bucket above top
+	link := func(n NI) {
+		b := out[n] - in[n] + offset
+		bucket[n] = b
+		prev[n] = -1
+		next[n] = head[b]
+		if head[b] >= 0 {
+			prev[head[b]] = n
+		}
+		head[b] = n
+		if b > top {
+			top = b
+		}
+	}
+	unlink := func(n NI) {
+		b := bucket[n]
+		if prev[n] >= 0 {
+			next[prev[n]] = next[n]
+		} else {
+			head[b] = next[n]
+		}
+		if next[n] >= 0 {
+			prev[next[n]] = prev[n]
+		}
+		bucket[n] = -1
+	}
+	var sinks, sources []NI
+	// place puts n in a queue or bucket according to its degrees.
+	// n must not be in a bucket.
+	place := func(n NI) {
+		switch {
+		case out[n] == 0:
+			sinks = append(sinks, n)
+		case in[n] == 0:
+			sources = append(sources, n)
+		default:
+			link(n)
+		}
+	}
+	for n := range a {
+		bucket[n] = -1
+		place(NI(n))
+	}
+	var removed Bits
+	// update re-places n after a change in its degrees.
+	update := func(n NI) {
+		if removed.Bit(n) == 1 {
+			return
+		}
+		if bucket[n] >= 0 {
+			unlink(n)
+			place(n)
+		}
+		// else n is already queued as a sink or source
+	}
+	remove := func(n NI) {
+		removed.SetBit(n, 1)
+		for _, to := range a[n] {
+			if to != n {
+				in[to]--
+				update(to)
+			}
+		}
+		for _, fr := range t.AdjacencyList[n] {
+			if fr != n {
+				out[fr]--
+				update(fr)
+			}
+		}
+	}
+	pos := make([]int, len(a))
+	first, last := 0, len(a)-1 // next positions at start and end
+	for first <= last {
+		var n NI
+		switch {
+		case len(sinks) > 0:
+			n = sinks[len(sinks)-1]
+			sinks = sinks[:len(sinks)-1]
+			pos[n] = last
+			last--
+		case len(sources) > 0:
+			n = sources[len(sources)-1]
+			sources = sources[:len(sources)-1]
+			pos[n] = first
+			first++
+		default:
+			// node of max out-degree minus in-degree to the start
+			for head[top] < 0 {
+				top--
+			}
+			n = head[top]
+			unlink(n)
+			pos[n] = first
+			first++
+		}
+		remove(n)
+	}
+	for fr, to := range a {
+		for _, to := range to {
+			if pos[to] <= pos[fr] {
+				arcs = append(arcs, Edge{NI(fr), to})
+			}
+		}
+	}
+	return
+}
+
 // MaximalNonBranchingPaths finds all paths in a directed graph that are
 // "maximal" and "non-branching".
 //
@@ -297,6 +562,107 @@ func (g Directed) MaximalNonBranchingPaths(emit func([]NI) bool) {
 	}
 }
 
+// ReachabilitySparsifier constructs a graph with the same reachability as g
+// but typically with fewer arcs.
+//
+// The result has the same nodes as g.  Nodes of each strongly connected
+// component of g are linked in a simple cycle in the result.  Between
+// components, arcs correspond to the transitive reduction of the
+// condensation, each linking the first listed nodes of the two components.
+// A node that is a component by itself keeps a loop if it has one in g.
+//
+// The result has the same transitive closure as g, but in general is not a
+// subgraph of g.  Use ReachabilityMatrix to verify or query reachability.
+func (g Directed) ReachabilitySparsifier() Directed {
+	a := g.AdjacencyList
+	scc, cd := g.TarjanCondensation()
+	s := make(AdjacencyList, len(a))
+	for _, c := range scc {
+		if len(c) == 1 {
+			if ok, _ := g.HasArc(c[0], c[0]); ok {
+				s[c[0]] = []NI{c[0]}
+			}
+			continue
+		}
+		last := c[len(c)-1]
+		for _, n := range c {
+			s[last] = append(s[last], n)
+			last = n
+		}
+	}
+	for cf, tos := range reduceOrderedDAG(cd) {
+		fr := scc[cf][0]
+		for _, ct := range tos {
+			s[fr] = append(s[fr], scc[ct][0])
+		}
+	}
+	return Directed{s}
+}
+
+// reduceOrderedDAG computes the transitive reduction of a DAG where node
+// numbers are a topological ordering, that is, all arcs lead from lower
+// numbered nodes to higher numbered nodes.  Parallel arcs are removed as well.
+func reduceOrderedDAG(g AdjacencyList) AdjacencyList {
+	r := make(AdjacencyList, len(g))
+	reach := make([]Bits, len(g)) // nodes reachable, excluding self
+	for n := len(g) - 1; n >= 0; n-- {
+		// visiting successors in topological order, an arc is redundant
+		// if its head is reachable through an earlier successor.
+		tos := append(NodeList{}, g[n]...)
+		sort.Sort(tos)
+		rn := &reach[n]
+		for _, to := range tos {
+			if rn.Bit(to) == 0 {
+				r[n] = append(r[n], to)
+				rn.Or(*rn, reach[to])
+				rn.SetBit(to, 1)
+			}
+		}
+	}
+	return r
+}
+
+// TransitiveReduction computes the transitive reduction of a DAG.
+//
+// The transitive reduction is the unique graph with the fewest arcs having
+// the same reachability as g.  For a DAG it is a subgraph of g, keeping
+// exactly the arcs fr->to for which there is no other path from fr to to.
+// Parallel arcs are reduced to a single arc.  Arcs from each node of the
+// result are listed in topological order of their heads.
+//
+// If g is cyclic, the transitive reduction is not unique and r is returned
+// as the zero value with ok false.  See ReachabilitySparsifier for a
+// similar result for cyclic graphs.
+func (g Directed) TransitiveReduction() (r Directed, ok bool) {
+	ord, cycle := g.Topological()
+	if cycle != nil {
+		return
+	}
+	// renumber nodes by position in ord so reduceOrderedDAG can be used
+	a := g.AdjacencyList
+	pos := make([]NI, len(a))
+	for i, n := range ord {
+		pos[n] = NI(i)
+	}
+	o := make(AdjacencyList, len(a))
+	for fr, to := range a {
+		p := make([]NI, len(to))
+		for i, to := range to {
+			p[i] = pos[to]
+		}
+		o[pos[fr]] = p
+	}
+	ro := reduceOrderedDAG(o)
+	ra := make(AdjacencyList, len(a))
+	for pf, to := range ro {
+		fr := ord[pf]
+		for _, pt := range to {
+			ra[fr] = append(ra[fr], ord[pt])
+		}
+	}
+	return Directed{ra}, true
+}
+
 // Undirected returns copy of g augmented as needed to make it undirected.
 func (g Directed) Undirected() Undirected {
 	c, _ := g.AdjacencyList.Copy()                  // start with a copy
@@ -538,6 +904,133 @@ func (g LabeledDirected) UnlabeledTranspose() (t Directed, ma int) {
 	return Directed{ta}, ma
 }
 
+// TopoOrder maintains a topological ordering of a directed acyclic graph
+// as arcs are added.
+//
+// Construct with NewTopoOrder, then add arcs with AddArcOrdered.  Exported
+// fields are valid after construction and after each call to AddArcOrdered
+// but should not be modified directly.
+type TopoOrder struct {
+	Directed       // the graph, always acyclic
+	Order    []NI  // nodes in topological order
+	Pos      []int // Pos[n] is the position of node n in Order
+	tr       AdjacencyList
+}
+
+// NewTopoOrder constructs a TopoOrder on graph g.
+//
+// The graph is retained and will be modified by AddArcOrdered.  If g is
+// cyclic, NewTopoOrder returns nil and a cycle found in g.
+func NewTopoOrder(g Directed) (t *TopoOrder, cycle []NI) {
+	ord, cycle := g.Topological()
+	if cycle != nil {
+		return nil, cycle
+	}
+	pos := make([]int, len(ord))
+	for i, n := range ord {
+		pos[n] = i
+	}
+	tr, _ := g.Transpose()
+	return &TopoOrder{g, ord, pos, tr.AdjacencyList}, nil
+}
+
+// AddArcOrdered adds the arc fr->to, if it does not create a cycle.
+//
+// If the arc would create a cycle, the graph is unchanged and AddArcOrdered
+// returns false.  Otherwise it adds the arc, updates the topological ordering
+// as needed and returns true.
+//
+// The ordering is maintained with the algorithm of Pearce and Kelly, which
+// searches and reorders only nodes between to and fr in the current ordering.
+// Nodes fr and to must already be in the graph.
+func (t *TopoOrder) AddArcOrdered(fr, to NI) (ok bool) {
+	if fr == to {
+		return false
+	}
+	g := t.AdjacencyList
+	pos := t.Pos
+	lb, ub := pos[to], pos[fr]
+	if lb < ub {
+		// the arc goes backward in the current ordering.  search forward
+		// from to for nodes affected, or a path to fr.
+		var vis Bits
+		var fwd, bwd []NI
+		var dff func(NI) bool
+		dff = func(n NI) bool {
+			vis.SetBit(n, 1)
+			fwd = append(fwd, n)
+			for _, s := range g[n] {
+				if s == fr {
+					return false
+				}
+				if vis.Bit(s) == 0 && pos[s] < ub && !dff(s) {
+					return false
+				}
+			}
+			return true
+		}
+		if !dff(to) {
+			return false
+		}
+		// search backward from fr
+		var dfb func(NI)
+		dfb = func(n NI) {
+			vis.SetBit(n, 1)
+			bwd = append(bwd, n)
+			for _, p := range t.tr[n] {
+				if vis.Bit(p) == 0 && pos[p] > lb {
+					dfb(p)
+				}
+			}
+		}
+		dfb(fr)
+		// reassign the positions held by the affected nodes, placing
+		// the backward set before the forward set.
+		sort.Sort(posOrder{bwd, pos})
+		sort.Sort(posOrder{fwd, pos})
+		nodes := append(bwd, fwd...)
+		x := make([]int, len(nodes))
+		for i, n := range nodes {
+			x[i] = pos[n]
+		}
+		sort.Ints(x)
+		for i, n := range nodes {
+			pos[n] = x[i]
+			t.Order[x[i]] = n
+		}
+	}
+	g[fr] = append(g[fr], to)
+	t.tr[to] = append(t.tr[to], fr)
+	return true
+}
+
+// posOrder sorts nodes by position in a topological ordering.
+type posOrder struct {
+	nodes []NI
+	pos   []int
+}
+
+func (p posOrder) Len() int           { return len(p.nodes) }
+func (p posOrder) Less(i, j int) bool { return p.pos[p.nodes[i]] < p.pos[p.nodes[j]] }
+func (p posOrder) Swap(i, j int)      { p.nodes[i], p.nodes[j] = p.nodes[j], p.nodes[i] }
+
+// niHeap implements container/heap, a min-heap of node numbers.
+type niHeap []NI
+
+func (h niHeap) Len() int           { return len(h) }
+func (h niHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h niHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *niHeap) Push(x interface{}) { *h = append(*h, x.(NI)) }
+
+func (h *niHeap) Pop() interface{} {
+	old := *h
+	last := len(old) - 1
+	x := old[last]
+	*h = old[:last]
+	return x
+}
+
 // DominanceFrontiers holds dominance frontiers for all nodes in some graph.
 // The frontier for a given node is a set of nodes, represented here as a map.
 type DominanceFrontiers []map[NI]struct{}
@@ -566,6 +1059,17 @@ func (d DominanceFrontiers) Closure(s map[NI]struct{}) map[NI]struct{} {
 	return c
 }
 
+// Reachability holds reachable node sets for all nodes in some graph.
+//
+// Reachability is the return type of TransitiveClosure.  Element n is the
+// set of nodes reachable from node n.
+type Reachability []Bits
+
+// Reaches returns true if to is reachable from fr.
+func (r Reachability) Reaches(fr, to NI) bool {
+	return r[fr].Bit(to) == 1
+}
+
 // Dominators holds immediate dominators.
 //
 // Dominators is a return type from methods Dominators, PostDominators, and
@@ -9,6 +9,11 @@ package graph
 // The RO means read only and it is upper case RO to slow you down a bit
 // in case you start to edit the file.
 
+import (
+	"container/heap"
+	"math"
+)
+
 // Balanced returns true if for every node in g, in-degree equals out-degree.
 //
 // There are equivalent labeled and unlabeled versions of this method.
@@ -30,6 +35,22 @@ func (g Directed) Copy() (c Directed, ma int) {
 	return Directed{l}, s
 }
 
+// Cycle finds a cycle in g.
+//
+// If g is cyclic, Cycle returns found = true and cycle as the node sequence
+// of a cycle, starting and ending with an arc to the first node, which is
+// not repeated at the end.  A loop is returned as a single node.  If g is
+// acyclic, Cycle returns found = false and a nil cycle.
+//
+// Cycle uses the depth first search of Topological.  Also see Cyclic,
+// which returns an arc of a cycle.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) Cycle() (cycle []NI, found bool) {
+	_, cycle = g.Topological()
+	return cycle, cycle != nil
+}
+
 // Cyclic determines if g contains a cycle, a non-empty path from a node
 // back to itself.
 //
@@ -266,7 +287,11 @@ func (g Directed) FromList() (*FromList, NI) {
 	return &FromList{Paths: paths}, -1
 }
 
-// InDegree computes the in-degree of each node in g
+// InDegree computes the in-degree of each node in g.
+//
+// In-degrees are counted in a single pass over the arcs of g without
+// constructing the transpose.  A loop counts as one arc into its node and
+// parallel arcs are each counted.
 //
 // There are equivalent labeled and unlabeled versions of this method.
 func (g Directed) InDegree() []int {
@@ -307,6 +332,134 @@ func (g Directed) IsTree(root NI) (isTree, allTree bool) {
 	return isTree, isTree && v.Zero()
 }
 
+// Kosaraju identifies strongly connected components in a directed graph using
+// Kosaraju's algorithm.
+//
+// A first depth-first pass over g orders nodes by finish time.  A second pass
+// over the transpose of g, taking nodes in reverse finish order, collects
+// each component.  Components are returned in reverse topological order of
+// the condensation, the same order in which Tarjan emits them.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+//
+// See also Tarjan.
+func (g Directed) Kosaraju() (scc [][]NI) {
+	a := g.AdjacencyList
+	var vis Bits
+	order := make([]NI, 0, len(a)) // nodes in order of finish time
+	var df func(NI)
+	df = func(n NI) {
+		vis.SetBit(n, 1)
+		for _, nb := range a[n] {
+			if vis.Bit(nb) == 0 {
+				df(nb)
+			}
+		}
+		order = append(order, n)
+	}
+	for n := range a {
+		if vis.Bit(NI(n)) == 0 {
+			df(NI(n))
+		}
+	}
+	tr, _ := g.Transpose()
+	t := tr.AdjacencyList
+	vis.Clear()
+	var c []NI
+	var dt func(NI)
+	dt = func(n NI) {
+		vis.SetBit(n, 1)
+		c = append(c, n)
+		for _, nb := range t[n] {
+			if vis.Bit(nb) == 0 {
+				dt(nb)
+			}
+		}
+	}
+	// components are found in forward topological order
+	for i := len(order) - 1; i >= 0; i-- {
+		if n := order[i]; vis.Bit(n) == 0 {
+			c = nil
+			dt(n)
+			scc = append(scc, c)
+		}
+	}
+	for i, j := 0, len(scc)-1; i < j; i, j = i+1, j-1 {
+		scc[i], scc[j] = scc[j], scc[i]
+	}
+	return
+}
+
+// PageRank computes PageRank of the nodes of g by power iteration.
+//
+// Argument damping is the damping factor, typically 0.85.  Iteration stops
+// after the given number of iterations or earlier if the L1 norm of the
+// change in rank drops below tol.  The rank of dangling nodes, nodes with
+// no arcs from them, is redistributed uniformly over all nodes.  Parallel
+// arcs each carry a share of rank.
+//
+// The result is indexed by node and sums to 1.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) PageRank(damping float64, iterations int, tol float64) (rank []float64) {
+	a := g.AdjacencyList
+	n := float64(len(a))
+	rank = make([]float64, len(a))
+	for i := range rank {
+		rank[i] = 1 / n
+	}
+	next := make([]float64, len(a))
+	for ; iterations > 0; iterations-- {
+		dangling := 0.
+		for fr, to := range a {
+			if len(to) == 0 {
+				dangling += rank[fr]
+			}
+		}
+		base := (1-damping)/n + damping*dangling/n
+		for i := range next {
+			next[i] = base
+		}
+		for fr, to := range a {
+			if len(to) == 0 {
+				continue
+			}
+			share := damping * rank[fr] / float64(len(to))
+			for _, to := range to {
+				next[to] += share
+			}
+		}
+		d := 0.
+		for i, r := range next {
+			d += math.Abs(r - rank[i])
+		}
+		rank, next = next, rank
+		if d < tol {
+			break
+		}
+	}
+	return
+}
+
+// ReachabilityMatrix returns the reachability of each node in g.
+//
+// Element n of the result is a bitmap of all nodes reachable from n.
+// A node is always considered reachable from itself.  The result is the
+// reflexive transitive closure of g, computed from TransitiveClosure by
+// adding the node itself to each bitmap.  Time and memory are as described
+// for TransitiveClosure.
+//
+// Test reachability from fr to to with rm[fr].Bit(to) == 1.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) ReachabilityMatrix() []Bits {
+	rm := g.TransitiveClosure()
+	for n := range rm {
+		rm[n].SetBit(NI(n), 1)
+	}
+	return rm
+}
+
 // Tarjan identifies strongly connected components in a directed graph using
 // Tarjan's algorithm.
 //
@@ -399,7 +552,13 @@ func (g Directed) TarjanForward() [][]NI {
 // TarjanCondensation returns strongly connected components and their
 // condensation graph.
 //
-// Components are ordered in a forward topological ordering.
+// Components are ordered in a forward topological ordering.  Node i of the
+// condensation cd represents component scc[i].  Cd has an arc from node i
+// to node j if g has any arc from a node of scc[i] to a node of scc[j].
+// Multiple such arcs are collapsed to a single arc and cd has no loops,
+// so Directed{cd} is a simple directed acyclic graph.
+//
+// There are equivalent labeled and unlabeled versions of this method.
 func (g Directed) TarjanCondensation() (scc [][]NI, cd AdjacencyList) {
 	scc = g.TarjanForward()
 	cd = make(AdjacencyList, len(scc))       // return value
@@ -560,6 +719,46 @@ func (g Directed) TopologicalKahn(tr Directed) (ordering, cycle []NI) {
 	return L, nil
 }
 
+// TopologicalLex computes the lexicographically least topological ordering
+// of a directed acyclic graph.
+//
+// TopologicalLex uses Kahn's algorithm, maintaining in-degrees and a min-heap
+// of nodes with no remaining incoming arcs.  Among nodes ready to be placed,
+// the lowest numbered node is always chosen next.  The result is
+// deterministic and independent of the order of arcs in g.
+//
+// For an acyclic graph, return value ordering is a permutation of node numbers
+// in topologically sorted order and cycle will be nil.  If the graph is found
+// to be cyclic, ordering will be nil and cycle will be the path of a found
+// cycle.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) TopologicalLex() (ordering, cycle []NI) {
+	rem := g.InDegree()
+	var S niHeap
+	for n, in := range rem {
+		if in == 0 {
+			S = append(S, NI(n))
+		}
+	}
+	// S is already sorted and so is a valid heap.
+	for len(S) > 0 {
+		n := heap.Pop(&S).(NI)
+		ordering = append(ordering, n)
+		for _, m := range g.AdjacencyList[n] {
+			rem[m]--
+			if rem[m] == 0 {
+				heap.Push(&S, m)
+			}
+		}
+	}
+	if len(ordering) < len(g.AdjacencyList) {
+		_, cycle = g.Topological()
+		return nil, cycle
+	}
+	return ordering, nil
+}
+
 // TopologicalSubgraph computes a topological ordering of a subgraph of a
 // directed acyclic graph.
 //
@@ -581,3 +780,52 @@ func (g Directed) TopologicalSubgraph(nodes []NI) (ordering, cycle []NI) {
 		return -1
 	})
 }
+
+// TransitiveClosure computes the transitive closure of g.
+//
+// Element n of the result has bits set for nodes reachable from n by paths
+// of one or more arcs.  A node is reachable from itself only if it is on a
+// cycle, that is, if it has a loop or is in a strongly connected component
+// of more than one node.  See ReachabilityMatrix for a result where a node
+// is always reachable from itself.
+//
+// Reachability is computed with bitmaps on the condensation of g, in
+// reverse topological order.  Each arc of the condensation costs a bitmap
+// Or, and each node a bitmap copy, of O(n/w) for n nodes and word size w.
+// Time is then about O((c+n)·n/w + m) for c arcs of the condensation and
+// m arcs of g.  The result takes O(n²/w) memory.
+//
+// Test reachability from fr to to with reach.Reaches(fr, to).
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) TransitiveClosure() (reach Reachability) {
+	a := g.AdjacencyList
+	scc, cd := g.TarjanCondensation()
+	// reachability from condensation nodes, including their own members
+	cr := make([]Bits, len(scc))
+	reach = make(Reachability, len(a))
+	for cn := len(scc) - 1; cn >= 0; cn-- {
+		var r Bits
+		for _, to := range cd[cn] {
+			r.Or(r, cr[to])
+		}
+		c := scc[cn]
+		cr[cn].Set(r)
+		for _, n := range c {
+			cr[cn].SetBit(n, 1)
+		}
+		cyclic := len(c) > 1
+		for _, to := range a[c[0]] {
+			if to == c[0] {
+				cyclic = true
+			}
+		}
+		if cyclic {
+			r = cr[cn]
+		}
+		for _, n := range c {
+			reach[n].Set(r)
+		}
+	}
+	return
+}
@@ -12,7 +12,10 @@ package graph_test
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
+	"testing"
 
 	"github.com/soniakeys/graph"
 )
@@ -39,6 +42,32 @@ func ExampleLabeledDirected_Balanced() {
 	// true
 }
 
+func ExampleLabeledDirected_Cycle() {
+	//   0
+	//  / \
+	// 1-->2-->3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+		3: {},
+	}}
+	fmt.Println(g.Cycle())
+
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g.LabeledAdjacencyList[3] = []graph.Half{{To: 1}}
+	fmt.Println(g.Cycle())
+
+	// Output:
+	// [] false
+	// [1 2 3] true
+}
+
 func ExampleLabeledDirected_Cyclic() {
 	//   0
 	//  / \
@@ -271,6 +300,181 @@ func ExampleLabeledDirected_PostDominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleLabeledDirected_ReachabilityMatrix() {
+	// 0-->1-->2-->4
+	//      \ /
+	//       3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 2}},
+		2: {{To: 3}, {To: 4}},
+		3: {{To: 1}},
+		4: {},
+	}}
+	for n, r := range g.ReachabilityMatrix() {
+		fmt.Println(n, r.Slice())
+	}
+	// Output:
+	// 0 [0 1 2 3 4]
+	// 1 [1 2 3 4]
+	// 2 [1 2 3 4]
+	// 3 [1 2 3 4]
+	// 4 [4]
+}
+
+func ExampleLabeledDirected_Kosaraju() {
+	// /---0---\
+	// |   |\--/
+	// |   v
+	// |   5<=>4---\
+	// |   |   |   |
+	// v   v   |   |
+	// 7<=>6   |   |
+	//     |   v   v
+	//     \-->3<--2
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 0}, {To: 5}, {To: 7}},
+		5: {{To: 4}, {To: 6}},
+		4: {{To: 5}, {To: 2}, {To: 3}},
+		7: {{To: 6}},
+		6: {{To: 7}, {To: 3}},
+		3: {{To: 1}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+	}}
+	for _, c := range g.Kosaraju() {
+		fmt.Println(c)
+	}
+	// Output:
+	// [2 1 3]
+	// [6 7]
+	// [5 4]
+	// [0]
+}
+
+func TestLabeledDirected_Cycle(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(10)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{To: graph.NI(r.Intn(n))})
+		}
+		c, found := g.Cycle()
+		if cyclic, _, _ := g.Cyclic(); found != cyclic {
+			t.Fatal("found", found, "Cyclic", cyclic)
+		}
+		if found != (len(c) > 0) {
+			t.Fatal("found", found, "cycle", c)
+		}
+		on := map[graph.NI]bool{}
+		for x, n := range c {
+			if on[n] {
+				t.Fatal("cycle", c, "repeats node", n)
+			}
+			on[n] = true
+			if ok, _ := g.HasArc(n, c[(x+1)%len(c)]); !ok {
+				t.Fatal("cycle", c, "missing arc from", n)
+			}
+		}
+	}
+}
+
+func TestLabeledDirected_Kosaraju(t *testing.T) {
+	g, _, _, err := graph.LabeledEuclidean(100, 180, 1, 100, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// component number of each node, by Tarjan and by Kosaraju
+	tc := make([]int, len(g.LabeledAdjacencyList))
+	for i, c := range g.TarjanForward() {
+		for _, n := range c {
+			tc[n] = i
+		}
+	}
+	kc := make([]int, len(g.LabeledAdjacencyList))
+	for i, c := range g.Kosaraju() {
+		for _, n := range c {
+			kc[n] = i
+		}
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if (tc[fr] == tc[to.To]) != (kc[fr] == kc[to.To]) {
+				t.Fatal("components differ for arc", fr, to.To)
+			}
+			if kc[fr] < kc[to.To] {
+				t.Fatal("arc", fr, to.To, "not in reverse topological order")
+			}
+		}
+	}
+}
+
+func ExampleLabeledDirected_PageRank() {
+	// 0-->1<--2
+	// ^   |
+	// |   v
+	// \---3   4 (dangling)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 3}},
+		2: {{To: 1}},
+		3: {{To: 0}},
+		4: {},
+	}}
+	for n, r := range g.PageRank(.85, 100, 1e-9) {
+		fmt.Printf("%d %.3f\n", n, r)
+	}
+	// Output:
+	// 0 0.298
+	// 1 0.321
+	// 2 0.036
+	// 3 0.309
+	// 4 0.036
+}
+
+func TestLabeledDirected_PageRank(t *testing.T) {
+	g, _, _, err := graph.LabeledEuclidean(100, 300, 1, 100, rand.New(rand.NewSource(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rank := g.PageRank(.85, 1000, 1e-12)
+	sum := 0.
+	for _, r := range rank {
+		sum += r
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatal("sum", sum)
+	}
+	// one more iteration should not change the result much
+	r2 := g.PageRank(.85, 1001, 0)
+	for n := range rank {
+		if math.Abs(rank[n]-r2[n]) > 1e-9 {
+			t.Fatal("not converged at node", n, rank[n], r2[n])
+		}
+	}
+}
+
+func TestLabeledDirected_ReachabilityMatrix(t *testing.T) {
+	g, _, _, err := graph.LabeledEuclidean(100, 180, 1, 100, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := g.ReachabilityMatrix()
+	for n := range g.LabeledAdjacencyList {
+		var b graph.Bits
+		g.DepthFirst(graph.NI(n), &b, nil)
+		if !rm[n].Eq(b) {
+			t.Fatal("node", n, "want", b.Slice(), "got", rm[n].Slice())
+		}
+	}
+}
+
 func ExampleLabeledDirected_Tarjan() {
 	// /---0---\
 	// |   |\--/
@@ -398,6 +602,63 @@ func ExampleLabeledDirected_Topological() {
 	// [] [1 2 3]
 }
 
+func TestLabeledDirected_Topological(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		g, _, _, err := graph.LabeledEuclidean(50, 100, 1, 100, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			// remove arcs to lower numbered nodes to make a DAG
+			for fr, to := range g.LabeledAdjacencyList {
+				k := to[:0]
+				for _, to := range to {
+					if int(to.To) > fr {
+						k = append(k, to)
+					}
+				}
+				g.LabeledAdjacencyList[fr] = k
+			}
+		}
+		o, c := g.Topological()
+		if len(c) > 0 {
+			if o != nil {
+				t.Fatal("ordering and cycle both returned")
+			}
+			for x, n := range c {
+				if ok, _ := g.HasArc(n, c[(x+1)%len(c)]); !ok {
+					t.Fatal("invalid cycle", c)
+				}
+			}
+			if i%2 == 0 {
+				t.Fatal("cycle in DAG")
+			}
+			continue
+		}
+		if len(o) != len(g.LabeledAdjacencyList) {
+			t.Fatal("ordering length", len(o))
+		}
+		pos := make([]int, len(o))
+		for x := range pos {
+			pos[x] = -1
+		}
+		for x, n := range o {
+			if pos[n] >= 0 {
+				t.Fatal("node", n, "repeated in ordering")
+			}
+			pos[n] = x
+		}
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, to := range to {
+				if pos[fr] >= pos[to.To] {
+					t.Fatal("arc", fr, to.To, "not ordered")
+				}
+			}
+		}
+	}
+}
+
 func ExampleLabeledDirected_TopologicalKahn() {
 	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
 		1: {{To: 2}},
@@ -415,6 +676,23 @@ func ExampleLabeledDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleLabeledDirected_TopologicalLex() {
+	//   3   1
+	//  / \ /
+	// 4   2   0
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		1: {{To: 2}},
+		3: {{To: 4}, {To: 2}},
+		4: {},
+	}}
+	fmt.Println(g.TopologicalLex())
+	g.LabeledAdjacencyList[2] = []graph.Half{{To: 3}}
+	fmt.Println(g.TopologicalLex())
+	// Output:
+	// [0 1 3 2 4] []
+	// [] [2 3]
+}
+
 func ExampleLabeledDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
@@ -434,3 +712,89 @@ func ExampleLabeledDirected_TopologicalSubgraph() {
 	// Output:
 	// [3 6 0 2 5] []
 }
+
+func ExampleLabeledDirected_TopologicalSubgraph_cycle() {
+	// arcs directected down unless otherwise indicated
+	// 0       1<-\
+	//  \     / \ /
+	//   2   3   4
+	//    \ / \
+	//     5   6
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 2}},
+		1: {{To: 3}, {To: 4}},
+		2: {{To: 5}},
+		3: {{To: 5}, {To: 6}},
+		4: {{To: 1}},
+		6: {},
+	}}
+	// nodes reachable from 0 and 3 are acyclic, 1 and 4 are not considered.
+	fmt.Println(g.TopologicalSubgraph([]graph.NI{0, 3}))
+	// nodes reachable from 4 include the cycle.
+	fmt.Println(g.TopologicalSubgraph([]graph.NI{0, 4}))
+	// Output:
+	// [3 6 0 2 5] []
+	// [] [4 1]
+}
+
+func ExampleLabeledDirected_TransitiveClosure() {
+	// 0-->1-->2-->4
+	//      \ /
+	//       3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 2}},
+		2: {{To: 3}, {To: 4}},
+		3: {{To: 1}},
+		4: {},
+	}}
+	reach := g.TransitiveClosure()
+	for n, r := range reach {
+		fmt.Println(n, r.Slice())
+	}
+	fmt.Println("0 reaches 0:", reach.Reaches(0, 0))
+	fmt.Println("1 reaches 1:", reach.Reaches(1, 1))
+	// Output:
+	// 0 [1 2 3 4]
+	// 1 [1 2 3 4]
+	// 2 [1 2 3 4]
+	// 3 [1 2 3 4]
+	// 4 []
+	// 0 reaches 0: false
+	// 1 reaches 1: true
+}
+
+func TestLabeledDirected_TransitiveClosure(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for m := r.Intn(2 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr], graph.Half{To: graph.NI(r.Intn(n))})
+		}
+		reach := g.TransitiveClosure()
+		for fr, to := range g.LabeledAdjacencyList {
+			// nodes reachable by one or more arcs, by breadth first search
+			var want graph.Bits
+			var q []graph.NI
+			for _, to := range to {
+				q = append(q, to.To)
+			}
+			for len(q) > 0 {
+				n := q[0]
+				q = q[1:]
+				if want.Bit(n) == 1 {
+					continue
+				}
+				want.SetBit(n, 1)
+				for _, to := range g.LabeledAdjacencyList[n] {
+					q = append(q, to.To)
+				}
+			}
+			if !reach[fr].Eq(want) {
+				t.Fatal("node", fr, "want", want.Slice(), "got", reach[fr].Slice())
+			}
+		}
+	}
+}
@@ -16,7 +16,10 @@ package graph_test
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
+	"testing"
 
 	"github.com/soniakeys/graph"
 )
@@ -43,6 +46,32 @@ func ExampleDirected_Balanced() {
 	// true
 }
 
+func ExampleDirected_Cycle() {
+	//   0
+	//  / \
+	// 1-->2-->3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {},
+	}}
+	fmt.Println(g.Cycle())
+
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g.AdjacencyList[3] = []graph.NI{1}
+	fmt.Println(g.Cycle())
+
+	// Output:
+	// [] false
+	// [1 2 3] true
+}
+
 func ExampleDirected_Cyclic() {
 	//   0
 	//  / \
@@ -275,6 +304,181 @@ func ExampleDirected_PostDominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleDirected_ReachabilityMatrix() {
+	// 0-->1-->2-->4
+	//      \ /
+	//       3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3, 4},
+		3: {1},
+		4: {},
+	}}
+	for n, r := range g.ReachabilityMatrix() {
+		fmt.Println(n, r.Slice())
+	}
+	// Output:
+	// 0 [0 1 2 3 4]
+	// 1 [1 2 3 4]
+	// 2 [1 2 3 4]
+	// 3 [1 2 3 4]
+	// 4 [4]
+}
+
+func ExampleDirected_Kosaraju() {
+	// /---0---\
+	// |   |\--/
+	// |   v
+	// |   5<=>4---\
+	// |   |   |   |
+	// v   v   |   |
+	// 7<=>6   |   |
+	//     |   v   v
+	//     \-->3<--2
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 5, 7},
+		5: {4, 6},
+		4: {5, 2, 3},
+		7: {6},
+		6: {7, 3},
+		3: {1},
+		1: {2},
+		2: {3},
+	}}
+	for _, c := range g.Kosaraju() {
+		fmt.Println(c)
+	}
+	// Output:
+	// [2 1 3]
+	// [6 7]
+	// [5 4]
+	// [0]
+}
+
+func TestDirected_Cycle(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(10)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr],
+				graph.NI(r.Intn(n)))
+		}
+		c, found := g.Cycle()
+		if cyclic, _, _ := g.Cyclic(); found != cyclic {
+			t.Fatal("found", found, "Cyclic", cyclic)
+		}
+		if found != (len(c) > 0) {
+			t.Fatal("found", found, "cycle", c)
+		}
+		on := map[graph.NI]bool{}
+		for x, n := range c {
+			if on[n] {
+				t.Fatal("cycle", c, "repeats node", n)
+			}
+			on[n] = true
+			if ok, _ := g.HasArc(n, c[(x+1)%len(c)]); !ok {
+				t.Fatal("cycle", c, "missing arc from", n)
+			}
+		}
+	}
+}
+
+func TestDirected_Kosaraju(t *testing.T) {
+	g, _, err := graph.Euclidean(100, 180, 1, 100, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// component number of each node, by Tarjan and by Kosaraju
+	tc := make([]int, len(g.AdjacencyList))
+	for i, c := range g.TarjanForward() {
+		for _, n := range c {
+			tc[n] = i
+		}
+	}
+	kc := make([]int, len(g.AdjacencyList))
+	for i, c := range g.Kosaraju() {
+		for _, n := range c {
+			kc[n] = i
+		}
+	}
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if (tc[fr] == tc[to]) != (kc[fr] == kc[to]) {
+				t.Fatal("components differ for arc", fr, to)
+			}
+			if kc[fr] < kc[to] {
+				t.Fatal("arc", fr, to, "not in reverse topological order")
+			}
+		}
+	}
+}
+
+func ExampleDirected_PageRank() {
+	// 0-->1<--2
+	// ^   |
+	// |   v
+	// \---3   4 (dangling)
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {3},
+		2: {1},
+		3: {0},
+		4: {},
+	}}
+	for n, r := range g.PageRank(.85, 100, 1e-9) {
+		fmt.Printf("%d %.3f\n", n, r)
+	}
+	// Output:
+	// 0 0.298
+	// 1 0.321
+	// 2 0.036
+	// 3 0.309
+	// 4 0.036
+}
+
+func TestDirected_PageRank(t *testing.T) {
+	g, _, err := graph.Euclidean(100, 300, 1, 100, rand.New(rand.NewSource(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rank := g.PageRank(.85, 1000, 1e-12)
+	sum := 0.
+	for _, r := range rank {
+		sum += r
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatal("sum", sum)
+	}
+	// one more iteration should not change the result much
+	r2 := g.PageRank(.85, 1001, 0)
+	for n := range rank {
+		if math.Abs(rank[n]-r2[n]) > 1e-9 {
+			t.Fatal("not converged at node", n, rank[n], r2[n])
+		}
+	}
+}
+
+func TestDirected_ReachabilityMatrix(t *testing.T) {
+	g, _, err := graph.Euclidean(100, 180, 1, 100, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := g.ReachabilityMatrix()
+	for n := range g.AdjacencyList {
+		var b graph.Bits
+		g.DepthFirst(graph.NI(n), &b, nil)
+		if !rm[n].Eq(b) {
+			t.Fatal("node", n, "want", b.Slice(), "got", rm[n].Slice())
+		}
+	}
+}
+
 func ExampleDirected_Tarjan() {
 	// /---0---\
 	// |   |\--/
@@ -402,6 +606,63 @@ func ExampleDirected_Topological() {
 	// [] [1 2 3]
 }
 
+func TestDirected_Topological(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		g, _, err := graph.Euclidean(50, 100, 1, 100, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			// remove arcs to lower numbered nodes to make a DAG
+			for fr, to := range g.AdjacencyList {
+				k := to[:0]
+				for _, to := range to {
+					if int(to) > fr {
+						k = append(k, to)
+					}
+				}
+				g.AdjacencyList[fr] = k
+			}
+		}
+		o, c := g.Topological()
+		if len(c) > 0 {
+			if o != nil {
+				t.Fatal("ordering and cycle both returned")
+			}
+			for x, n := range c {
+				if ok, _ := g.HasArc(n, c[(x+1)%len(c)]); !ok {
+					t.Fatal("invalid cycle", c)
+				}
+			}
+			if i%2 == 0 {
+				t.Fatal("cycle in DAG")
+			}
+			continue
+		}
+		if len(o) != len(g.AdjacencyList) {
+			t.Fatal("ordering length", len(o))
+		}
+		pos := make([]int, len(o))
+		for x := range pos {
+			pos[x] = -1
+		}
+		for x, n := range o {
+			if pos[n] >= 0 {
+				t.Fatal("node", n, "repeated in ordering")
+			}
+			pos[n] = x
+		}
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if pos[fr] >= pos[to] {
+					t.Fatal("arc", fr, to, "not ordered")
+				}
+			}
+		}
+	}
+}
+
 func ExampleDirected_TopologicalKahn() {
 	g := graph.Directed{graph.AdjacencyList{
 		1: {2},
@@ -419,6 +680,23 @@ func ExampleDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleDirected_TopologicalLex() {
+	//   3   1
+	//  / \ /
+	// 4   2   0
+	g := graph.Directed{graph.AdjacencyList{
+		1: {2},
+		3: {4, 2},
+		4: {},
+	}}
+	fmt.Println(g.TopologicalLex())
+	g.AdjacencyList[2] = []graph.NI{3}
+	fmt.Println(g.TopologicalLex())
+	// Output:
+	// [0 1 3 2 4] []
+	// [] [2 3]
+}
+
 func ExampleDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
@@ -438,3 +716,89 @@ func ExampleDirected_TopologicalSubgraph() {
 	// Output:
 	// [3 6 0 2 5] []
 }
+
+func ExampleDirected_TopologicalSubgraph_cycle() {
+	// arcs directected down unless otherwise indicated
+	// 0       1<-\
+	//  \     / \ /
+	//   2   3   4
+	//    \ / \
+	//     5   6
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2},
+		1: {3, 4},
+		2: {5},
+		3: {5, 6},
+		4: {1},
+		6: {},
+	}}
+	// nodes reachable from 0 and 3 are acyclic, 1 and 4 are not considered.
+	fmt.Println(g.TopologicalSubgraph([]graph.NI{0, 3}))
+	// nodes reachable from 4 include the cycle.
+	fmt.Println(g.TopologicalSubgraph([]graph.NI{0, 4}))
+	// Output:
+	// [3 6 0 2 5] []
+	// [] [4 1]
+}
+
+func ExampleDirected_TransitiveClosure() {
+	// 0-->1-->2-->4
+	//      \ /
+	//       3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3, 4},
+		3: {1},
+		4: {},
+	}}
+	reach := g.TransitiveClosure()
+	for n, r := range reach {
+		fmt.Println(n, r.Slice())
+	}
+	fmt.Println("0 reaches 0:", reach.Reaches(0, 0))
+	fmt.Println("1 reaches 1:", reach.Reaches(1, 1))
+	// Output:
+	// 0 [1 2 3 4]
+	// 1 [1 2 3 4]
+	// 2 [1 2 3 4]
+	// 3 [1 2 3 4]
+	// 4 []
+	// 0 reaches 0: false
+	// 1 reaches 1: true
+}
+
+func TestDirected_TransitiveClosure(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for m := r.Intn(2 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		reach := g.TransitiveClosure()
+		for fr, to := range g.AdjacencyList {
+			// nodes reachable by one or more arcs, by breadth first search
+			var want graph.Bits
+			var q []graph.NI
+			for _, to := range to {
+				q = append(q, to)
+			}
+			for len(q) > 0 {
+				n := q[0]
+				q = q[1:]
+				if want.Bit(n) == 1 {
+					continue
+				}
+				want.SetBit(n, 1)
+				for _, to := range g.AdjacencyList[n] {
+					q = append(q, to)
+				}
+			}
+			if !reach[fr].Eq(want) {
+				t.Fatal("node", fr, "want", want.Slice(), "got", reach[fr].Slice())
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ package graph_test
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/soniakeys/graph"
@@ -40,6 +41,77 @@ func ExampleDirected_EulerianCycle() {
 	// [0 1 2 1 2 2 0] <nil>
 }
 
+func ExampleDirected_ElementaryCycles() {
+	//   0-->1<--\
+	//   ^   |   |
+	//   |   v   |
+	//   \---2-->3--\
+	//           ^  |
+	//           \--/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {0, 3},
+		3: {1, 3},
+	}}
+	for _, c := range g.ElementaryCycles() {
+		fmt.Println(c)
+	}
+	// Output:
+	// [0 1 2]
+	// [1 2 3]
+	// [3]
+}
+
+func TestElementaryCycles(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(8)
+		a := make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			a[fr] = append(a[fr], graph.NI(r.Intn(n)))
+		}
+		// brute force, from each start visiting only greater nodes
+		want := map[string]bool{}
+		var p []graph.NI
+		var f func(n graph.NI)
+		f = func(n graph.NI) {
+			p = append(p, n)
+			for _, to := range a[n] {
+				switch {
+				case to == p[0]:
+					want[fmt.Sprint(p)] = true
+				case to < p[0]:
+				default:
+					on := false
+					for _, x := range p {
+						if x == to {
+							on = true
+						}
+					}
+					if !on {
+						f(to)
+					}
+				}
+			}
+			p = p[:len(p)-1]
+		}
+		for s := range a {
+			f(graph.NI(s))
+		}
+		got := graph.Directed{a}.ElementaryCycles()
+		if len(got) != len(want) {
+			t.Fatal(len(got), "cycles, want", len(want), got)
+		}
+		for _, c := range got {
+			if !want[fmt.Sprint(c)] {
+				t.Fatal("unexpected cycle", c)
+			}
+		}
+	}
+}
+
 func TestEulerianCycle(t *testing.T) {
 	same := func(a, b []graph.NI) bool {
 		if len(a) != len(b) {
@@ -141,6 +213,68 @@ func TestEulerianPath(t *testing.T) {
 	}
 }
 
+func ExampleDirected_FeedbackArcSet() {
+	//   0-->1-->2
+	//   ^   ^   |
+	//   |   |   v
+	//   4<--3<--/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {4, 1},
+		4: {0},
+	}}
+	fmt.Println(g.FeedbackArcSet())
+	// Output:
+	// [{2 3}]
+}
+
+func TestFeedbackArcSet(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(20)
+		a := make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			a[fr] = append(a[fr], graph.NI(r.Intn(n)))
+		}
+		fas := graph.Directed{a}.FeedbackArcSet()
+		// remove arcs, one for each listed
+		cut := map[graph.Edge]int{}
+		for _, e := range fas {
+			cut[e]++
+		}
+		d := make(graph.AdjacencyList, n)
+		for fr, to := range a {
+			for _, to := range to {
+				if e := (graph.Edge{graph.NI(fr), to}); cut[e] > 0 {
+					cut[e]--
+				} else {
+					d[fr] = append(d[fr], to)
+				}
+			}
+		}
+		for e, c := range cut {
+			if c > 0 {
+				t.Fatal("arc", e, "not in graph")
+			}
+		}
+		if cyclic, _, _ := (graph.Directed{d}).Cyclic(); cyclic {
+			t.Fatal("still cyclic")
+		}
+	}
+	// a long cycle.  time is linear, one arc breaks it.
+	const n = 1e5
+	a := make(graph.AdjacencyList, n)
+	for fr := range a {
+		a[fr] = []graph.NI{graph.NI((fr + 1) % n)}
+	}
+	if fas := (graph.Directed{a}).FeedbackArcSet(); len(fas) != 1 {
+		t.Fatal("long cycle, got", len(fas), "arcs")
+	}
+}
+
 func ExampleDirected_MaximalNonBranchingPaths() {
 	// 0-->1-->2-->3
 	//          \
@@ -165,6 +299,54 @@ func ExampleDirected_MaximalNonBranchingPaths() {
 	// [5 6 5]
 }
 
+func ExampleDirected_ReachabilitySparsifier() {
+	// 0-->1-->2-->3<->4
+	//  \   \-->--/   /
+	//   \----->-----/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 4},
+		1: {2, 3},
+		2: {3},
+		3: {4},
+		4: {3},
+	}}
+	s := g.ReachabilitySparsifier()
+	fmt.Println(s.AdjacencyList)
+	// Output:
+	// [[1] [2] [4] [4] [3]]
+}
+
+func TestReachabilitySparsifier(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		g, _, err := graph.Euclidean(50, 150, 1, 100, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// add some long range arcs
+		for j := 0; j < 20; j++ {
+			fr := graph.NI(r.Intn(50))
+			var b graph.Bits
+			g.DepthFirst(fr, &b, nil)
+			if to := b.Slice(); len(to) > 0 {
+				g.AdjacencyList[fr] = append(g.AdjacencyList[fr],
+					to[r.Intn(len(to))])
+			}
+		}
+		s := g.ReachabilitySparsifier()
+		gm := g.ReachabilityMatrix()
+		sm := s.ReachabilityMatrix()
+		for n := range gm {
+			if !gm[n].Eq(sm[n]) {
+				t.Fatal("reachability differs from node", n)
+			}
+		}
+		if s.ArcSize() > g.ArcSize() {
+			t.Fatal(s.ArcSize(), "arcs, more than", g.ArcSize())
+		}
+	}
+}
+
 func ExampleDirected_Transpose() {
 	g := graph.Directed{graph.AdjacencyList{
 		2: {0, 1},
@@ -181,6 +363,80 @@ func ExampleDirected_Transpose() {
 	// 2
 }
 
+func ExampleDirected_TransitiveReduction() {
+	// 0-->1-->3
+	// |\  ^   ^
+	// | \ |   |
+	// |  v|   |
+	// |   2   |
+	//  \------/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {3},
+		2: {1},
+		3: {},
+	}}
+	r, ok := g.TransitiveReduction()
+	fmt.Println(ok)
+	for fr, to := range r.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	g.AdjacencyList[3] = []graph.NI{0}
+	_, ok = g.TransitiveReduction()
+	fmt.Println(ok)
+	// Output:
+	// true
+	// 0 [2]
+	// 1 [3]
+	// 2 [1]
+	// 3 []
+	// false
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		// random DAG, arcs lead from lower to higher positions of perm
+		n := 1 + r.Intn(20)
+		perm := r.Perm(n)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for m := r.Intn(3 * n); m > 0; m-- {
+			x, y := r.Intn(n), r.Intn(n)
+			if x > y {
+				x, y = y, x
+			} else if x == y {
+				continue
+			}
+			fr := perm[x]
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(perm[y]))
+		}
+		red, ok := g.TransitiveReduction()
+		if !ok {
+			t.Fatal("DAG reported cyclic")
+		}
+		gm := g.ReachabilityMatrix()
+		rm := red.ReachabilityMatrix()
+		for n := range gm {
+			if !gm[n].Eq(rm[n]) {
+				t.Fatal("reachability differs from node", n)
+			}
+		}
+		for fr, to := range red.AdjacencyList {
+			for _, to := range to {
+				if ok, _ := g.HasArc(graph.NI(fr), to); !ok {
+					t.Fatal("arc", fr, to, "not in g")
+				}
+				// arc must not be implied by another successor
+				for _, other := range red.AdjacencyList[fr] {
+					if other != to && rm[other].Bit(to) == 1 {
+						t.Fatal("arc", fr, to, "redundant")
+					}
+				}
+			}
+		}
+	}
+}
+
 func ExampleDirected_Undirected() {
 	// arcs directed down:
 	//    0
@@ -326,6 +582,60 @@ func ExampleLabeledDirected_UnlabeledTranspose() {
 	// 2 arcs
 }
 
+func ExampleTopoOrder_AddArcOrdered() {
+	// start with 4 nodes, no arcs
+	t, _ := graph.NewTopoOrder(graph.Directed{make(graph.AdjacencyList, 4)})
+	fmt.Println(t.Order)
+	fmt.Println(t.AddArcOrdered(0, 1), t.Order)
+	fmt.Println(t.AddArcOrdered(1, 2), t.Order)
+	fmt.Println(t.AddArcOrdered(2, 0), t.Order) // would create a cycle
+	fmt.Println(t.AddArcOrdered(2, 3), t.Order)
+	fmt.Println(t.AdjacencyList)
+	// Output:
+	// [3 2 1 0]
+	// true [3 2 0 1]
+	// true [3 0 1 2]
+	// false [3 0 1 2]
+	// true [0 1 2 3]
+	// [[1] [2] [3] []]
+}
+
+func TestTopoOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const n = 30
+	to, _ := graph.NewTopoOrder(graph.Directed{make(graph.AdjacencyList, n)})
+	nOk := 0
+	for i := 0; i < 300; i++ {
+		fr, nb := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+		// cycle check by brute force: would arc fr->nb close a path nb->fr?
+		var b graph.Bits
+		to.DepthFirst(nb, &b, nil)
+		want := b.Bit(fr) == 0
+		if got := to.AddArcOrdered(fr, nb); got != want {
+			t.Fatal("AddArcOrdered", fr, nb, "returned", got)
+		}
+		if want {
+			nOk++
+		}
+		// validate ordering
+		for x, n := range to.Order {
+			if to.Pos[n] != x {
+				t.Fatal("Pos inconsistent with Order")
+			}
+		}
+		for fr, nbs := range to.AdjacencyList {
+			for _, nb := range nbs {
+				if to.Pos[fr] >= to.Pos[nb] {
+					t.Fatal("arc", fr, nb, "violates ordering", to.Order)
+				}
+			}
+		}
+	}
+	if nOk == 0 {
+		t.Fatal("no arcs added")
+	}
+}
+
 func ExampleDominators_Frontiers() {
 	//   0
 	//   |
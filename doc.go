@@ -45,7 +45,8 @@
 // In contrast to Half, the type Edge represents both ends of an edge (but
 // no label.)  The type LabeledEdge adds the label.  The type WeightedEdgeList
 // bundles a list of LabeledEdges with a WeightFunc.  WeightedEdgeList is
-// currently only used by Kruskal methods.
+// used mostly by Kruskal methods but can be converted to a
+// LabeledAdjacencyList or LabeledUndirected graph.
 //
 // FromList is a compact rooted tree (or forest) respresentation.  Like
 // AdjacencyList and LabeledAdjacencyList, it is a list with one element for
@@ -9,6 +9,9 @@
 // There is no goal to provide a rich API to the many capabilities of the
 // dot format.  Someday, maybe, another package.  Not now.
 //
+// For reading, ReadAdjacencyList accepts a simple subset of the dot format,
+// enough to recover graphs written by this package.
+//
 // The scheme
 //
 // The dot package is a separate package from graph.  It includes graph;
@@ -36,6 +39,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/soniakeys/graph"
 )
@@ -156,14 +161,20 @@ func writeAL(g graph.AdjacencyList, w io.Writer, cf *Config) (err error) {
 	}
 	if cf.NodePos != nil {
 		_, err = fmt.Fprint(b, cf.Indent, "node [shape=point]\n")
-		for n := range g {
+		for _, n := range nodeOrder(len(g), cf) {
 			_, err = fmt.Fprintf(b, "%s%d [pos=\"%s!\"]\n",
-				cf.Indent, n, cf.NodePos(graph.NI(n)))
+				cf.Indent, n, cf.NodePos(n))
 			if err != nil {
 				return
 			}
 		}
 	}
+	if err = writeNodeAttrs(len(g), cf, b); err != nil {
+		return
+	}
+	if err = writeClusters(len(g), cf, b); err != nil {
+		return
+	}
 	var iso graph.Bits
 	if cf.Isolated {
 		iso = g.IsolatedNodes()
@@ -205,9 +216,131 @@ func writeTail(b *bufio.Writer) error {
 	return b.Flush()
 }
 
+// nodeOrder returns the order for writing nodes 0 through order-1.
+//
+// Nodes of cf.NodeOrder come first, then remaining nodes in increasing
+// order.
+func nodeOrder(order int, cf *Config) []graph.NI {
+	o := make([]graph.NI, 0, order)
+	var done graph.Bits
+	for _, n := range cf.NodeOrder {
+		if n >= 0 && int(n) < order && done.Bit(n) == 0 {
+			o = append(o, n)
+			done.SetBit(n, 1)
+		}
+	}
+	for n := graph.NI(0); int(n) < order; n++ {
+		if done.Bit(n) == 0 {
+			o = append(o, n)
+		}
+	}
+	return o
+}
+
+// writeNodeAttrs writes node statements for nodes with attributes.
+func writeNodeAttrs(order int, cf *Config, b *bufio.Writer) error {
+	if cf.NodeAttr == nil {
+		return nil
+	}
+	for _, n := range nodeOrder(order, cf) {
+		if a := cf.NodeAttr(n); len(a) > 0 {
+			_, err := fmt.Fprintf(b, "%s%s%s\n",
+				cf.Indent, cf.NodeID(n), attrList(quoteAttrs(a)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeClusters writes a subgraph for each cluster.
+func writeClusters(order int, cf *Config, b *bufio.Writer) error {
+	if cf.Clusters == nil {
+		return nil
+	}
+	m := map[int][]graph.NI{}
+	var cs []int
+	for _, n := range nodeOrder(order, cf) {
+		c := cf.Clusters(n)
+		if c < 0 {
+			continue
+		}
+		if _, ok := m[c]; !ok {
+			cs = append(cs, c)
+		}
+		m[c] = append(m[c], n)
+	}
+	sort.Ints(cs)
+	for _, c := range cs {
+		_, err := fmt.Fprintf(b, "%ssubgraph cluster_%d {\n", cf.Indent, c)
+		if err != nil {
+			return err
+		}
+		for _, n := range m[c] {
+			_, err = fmt.Fprintf(b, "%s%s%s\n", cf.Indent, cf.Indent, cf.NodeID(n))
+			if err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprintf(b, "%s}\n", cf.Indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attrList formats a dot attribute list, with a leading space.
+// It returns an empty string for an empty list.
+func attrList(a []AttrVal) string {
+	if len(a) == 0 {
+		return ""
+	}
+	s := " ["
+	for i, av := range a {
+		if i > 0 {
+			s += ", "
+		}
+		s += av.Attr + " = " + av.Val
+	}
+	return s + "]"
+}
+
+// labelAttrs returns attributes for a labeled edge.
+func labelAttrs(fr, to graph.NI, l graph.LI, cf *Config) []AttrVal {
+	return append([]AttrVal{{"label", cf.EdgeLabel(l)}},
+		edgeAttrs(fr, to, l, cf)...)
+}
+
+// edgeAttrs returns attributes from cf.EdgeAttr, if specified.  For
+// undirected output, the function is called with fr <= to.
+func edgeAttrs(fr, to graph.NI, l graph.LI, cf *Config) []AttrVal {
+	if cf.EdgeAttr == nil {
+		return nil
+	}
+	if !cf.Directed && to < fr {
+		fr, to = to, fr
+	}
+	return quoteAttrs(cf.EdgeAttr(fr, to, l))
+}
+
+// quoteAttrs converts key-value pairs to attributes with values as dot
+// format quoted strings.
+func quoteAttrs(kv []AttrKV) []AttrVal {
+	if len(kv) == 0 {
+		return nil
+	}
+	a := make([]AttrVal, len(kv))
+	for i, p := range kv {
+		a[i] = AttrVal{p.Key, `"` + strings.Replace(p.Val, `"`, `\"`, -1) + `"`}
+	}
+	return a
+}
+
 func writeALDirected(g graph.AdjacencyList, cf *Config, iso graph.Bits, b *bufio.Writer) error {
-	for fr, to := range g {
-		err := writeALEdgeStmt(graph.NI(fr), to, "->", cf, iso, b)
+	for _, fr := range nodeOrder(len(g), cf) {
+		to := g[fr]
+		err := writeALEdgeStmt(fr, to, "->", cf, iso, b)
 		if err != nil {
 			return err
 		}
@@ -223,6 +356,17 @@ func writeALEdgeStmt(fr graph.NI, to []graph.NI, op string, cf *Config, iso grap
 		}
 		return
 	}
+	if cf.EdgeAttr != nil { // separate statements to allow attributes
+		for _, to := range to {
+			_, err = fmt.Fprintf(b, "%s%s %s %s%s\n",
+				cf.Indent, cf.NodeID(fr), op, cf.NodeID(to),
+				attrList(edgeAttrs(fr, to, 0, cf)))
+			if err != nil {
+				return
+			}
+		}
+		return
+	}
 	if len(to) == 1 { // fast path
 		_, err = fmt.Fprintf(b, "%s%s %s %s\n",
 			cf.Indent, cf.NodeID(fr), op, cf.NodeID(to[0]))
@@ -283,19 +427,20 @@ func writeALEdgeStmt(fr graph.NI, to []graph.NI, op string, cf *Config, iso grap
 func writeALUndirected(g graph.AdjacencyList, cf *Config, iso graph.Bits, b *bufio.Writer) error {
 	// Similar code in undir.go at IsUndirected
 	unpaired := make(graph.AdjacencyList, len(g))
-	for fr, to := range g {
+	for _, fr := range nodeOrder(len(g), cf) {
+		to := g[fr]
 		// first collect unpaired subset of to
 		var uto []graph.NI
 	arc: // for each arc in g
 		for _, to := range to {
-			if to == graph.NI(fr) {
+			if to == fr {
 				uto = append(uto, to) // loop
 				continue
 			}
 			// search unpaired arcs
 			ut := unpaired[to]
 			for i, u := range ut {
-				if u == graph.NI(fr) { // found reciprocal
+				if u == fr { // found reciprocal
 					last := len(ut) - 1
 					ut[i] = ut[last]
 					unpaired[to] = ut[:last]
@@ -306,7 +451,7 @@ func writeALUndirected(g graph.AdjacencyList, cf *Config, iso graph.Bits, b *buf
 			uto = append(uto, to)
 			unpaired[fr] = append(unpaired[fr], to)
 		}
-		err := writeALEdgeStmt(graph.NI(fr), uto, "--", cf, iso, b)
+		err := writeALEdgeStmt(fr, uto, "--", cf, iso, b)
 		if err != nil {
 			return err
 		}
@@ -343,14 +488,20 @@ func writeLAL(g graph.LabeledAdjacencyList, w io.Writer, cf *Config) (err error)
 	}
 	if cf.NodePos != nil {
 		_, err = fmt.Fprint(b, cf.Indent, "node [shape=point]\n")
-		for n := range g {
+		for _, n := range nodeOrder(len(g), cf) {
 			_, err = fmt.Fprintf(b, "%s%d [pos=\"%s!\"]\n",
-				cf.Indent, n, cf.NodePos(graph.NI(n)))
+				cf.Indent, n, cf.NodePos(n))
 			if err != nil {
 				return
 			}
 		}
 	}
+	if err = writeNodeAttrs(len(g), cf, b); err != nil {
+		return
+	}
+	if err = writeClusters(len(g), cf, b); err != nil {
+		return
+	}
 	var iso graph.Bits
 	if cf.Isolated {
 		iso = g.IsolatedNodes()
@@ -369,8 +520,9 @@ func writeLAL(g graph.LabeledAdjacencyList, w io.Writer, cf *Config) (err error)
 }
 
 func writeLALDirected(g graph.LabeledAdjacencyList, cf *Config, iso graph.Bits, b *bufio.Writer) error {
-	for fr, to := range g {
-		err := writeLALEdgeStmt(graph.NI(fr), to, "->", cf, iso, b)
+	for _, fr := range nodeOrder(len(g), cf) {
+		to := g[fr]
+		err := writeLALEdgeStmt(fr, to, "->", cf, iso, b)
 		if err != nil {
 			return err
 		}
@@ -387,9 +539,9 @@ func writeLALEdgeStmt(fr graph.NI, to []graph.Half, op string, cf *Config, iso g
 		return
 	}
 	for _, to := range to {
-		_, err = fmt.Fprintf(b, "%s%s %s %s [label = %s]\n",
+		_, err = fmt.Fprintf(b, "%s%s %s %s%s\n",
 			cf.Indent, cf.NodeID(fr), op, cf.NodeID(to.To),
-			cf.EdgeLabel(to.Label))
+			attrList(labelAttrs(fr, to.To, to.Label, cf)))
 		if err != nil {
 			return
 		}
@@ -400,19 +552,20 @@ func writeLALEdgeStmt(fr graph.NI, to []graph.Half, op string, cf *Config, iso g
 func writeLALUndirected(g graph.LabeledAdjacencyList, cf *Config, iso graph.Bits, b *bufio.Writer) error {
 	// Similar code in undir.go at IsUndirected
 	unpaired := make(graph.LabeledAdjacencyList, len(g))
-	for fr, to := range g {
+	for _, fr := range nodeOrder(len(g), cf) {
+		to := g[fr]
 		// first collect unpaired subset of to
 		var uto []graph.Half
 	arc: // for each arc in g
 		for _, to := range to {
-			if to.To == graph.NI(fr) {
+			if to.To == fr {
 				uto = append(uto, to) // loop
 				continue
 			}
 			// search unpaired arcs
 			ut := unpaired[to.To]
 			for i, u := range ut {
-				if u.To == graph.NI(fr) && u.Label == to.Label { // found reciprocal
+				if u.To == fr && u.Label == to.Label { // found reciprocal
 					last := len(ut) - 1
 					ut[i] = ut[last]
 					unpaired[to.To] = ut[:last]
@@ -423,7 +576,7 @@ func writeLALUndirected(g graph.LabeledAdjacencyList, cf *Config, iso graph.Bits
 			uto = append(uto, to)
 			unpaired[fr] = append(unpaired[fr], to)
 		}
-		err := writeLALEdgeStmt(graph.NI(fr), uto, "--", cf, iso, b)
+		err := writeLALEdgeStmt(fr, uto, "--", cf, iso, b)
 		if err != nil {
 			return err
 		}
@@ -446,6 +599,12 @@ func writeFromList(f graph.FromList, w io.Writer, options []func(*Config)) error
 	if err := writeHead(&cf, b); err != nil {
 		return err
 	}
+	if err := writeNodeAttrs(len(f.Paths), &cf, b); err != nil {
+		return err
+	}
+	if err := writeClusters(len(f.Paths), &cf, b); err != nil {
+		return err
+	}
 	var iso graph.Bits
 	if cf.Isolated {
 		iso = f.IsolatedNodes()
@@ -462,8 +621,9 @@ func writeFromList(f graph.FromList, w io.Writer, options []func(*Config)) error
 			}
 			continue
 		}
-		_, err := fmt.Fprintf(b, "%s%s -> %s\n",
-			cf.Indent, cf.NodeID(n), cf.NodeID(fr))
+		_, err := fmt.Fprintf(b, "%s%s -> %s%s\n",
+			cf.Indent, cf.NodeID(n), cf.NodeID(fr),
+			attrList(edgeAttrs(n, fr, 0, &cf)))
 		if err != nil {
 			return err
 		}
@@ -507,6 +667,12 @@ func writeWeightedEdgeList(g graph.WeightedEdgeList, w io.Writer, options []func
 	if err := writeHead(&cf, b); err != nil {
 		return err
 	}
+	if err := writeNodeAttrs(g.Order, &cf, b); err != nil {
+		return err
+	}
+	if err := writeClusters(g.Order, &cf, b); err != nil {
+		return err
+	}
 	wf := writeWELNoRecip
 	if cf.UndirectArcs || cf.Directed {
 		wf = writeWELAllArcs
@@ -526,9 +692,9 @@ edge:
 		for i, u := range u2 {
 			if u.To == e.N1 && u.Label == e.LI { // found reciprocal
 				// write the edge
-				_, err := fmt.Fprintf(b, "%s%s -- %s [label = %s]\n",
+				_, err := fmt.Fprintf(b, "%s%s -- %s%s\n",
 					cf.Indent, cf.NodeID(e.N2), cf.NodeID(e.N1),
-					cf.EdgeLabel(e.LI))
+					attrList(labelAttrs(e.N2, e.N1, e.LI, cf)))
 				if err != nil {
 					return err
 				}
@@ -556,9 +722,9 @@ func writeWELAllArcs(g graph.WeightedEdgeList, cf *Config, b *bufio.Writer) erro
 		op = "->"
 	}
 	for _, e := range g.Edges {
-		_, err := fmt.Fprintf(b, "%s%s %s %s [label = %s]\n",
+		_, err := fmt.Fprintf(b, "%s%s %s %s%s\n",
 			cf.Indent, cf.NodeID(e.N1), op, cf.NodeID(e.N2),
-			cf.EdgeLabel(e.LI))
+			attrList(labelAttrs(e.N1, e.N2, e.LI, cf)))
 		if err != nil {
 			return err
 		}
@@ -4,6 +4,7 @@
 package dot
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/soniakeys/graph"
@@ -15,6 +16,16 @@ type AttrVal struct {
 	Val  string
 }
 
+// AttrKV is a key-value pair for node and edge attributes.
+//
+// Unlike AttrVal, the value is not written as given but as a dot format
+// quoted string, so values such as "light blue" need no quoting by the
+// caller.
+type AttrKV struct {
+	Key string
+	Val string
+}
+
 // Config holds options that control the dot output.
 //
 // See Overview/Scheme for an overview of how this works.  Generally you will
@@ -22,12 +33,16 @@ type AttrVal struct {
 // for each member.  To set a member, pass the option function as an optional
 // argument to a Write or String function.
 type Config struct {
+	Clusters     func(graph.NI) int
 	Directed     bool
+	EdgeAttr     func(fr, to graph.NI, l graph.LI) []AttrKV
 	EdgeLabel    func(graph.LI) string
 	GraphAttr    []AttrVal
 	Indent       string
 	Isolated     bool
+	NodeAttr     func(graph.NI) []AttrKV
 	NodeID       func(graph.NI) string
+	NodeOrder    []graph.NI
 	NodePos      func(graph.NI) string
 	UndirectArcs bool
 }
@@ -42,6 +57,20 @@ var Defaults = Config{
 	NodeID:    func(n graph.NI) string { return strconv.Itoa(int(n)) },
 }
 
+// Clusters specifies a function assigning nodes to clusters.
+//
+// The function is called for each node of the graph and returns a cluster
+// number.  For each distinct non-negative cluster number c, a subgraph named
+// cluster_c is written listing the nodes of the cluster.  Subgraphs are
+// written in order of cluster number, before edge statements.  Nodes with a
+// negative cluster number are not placed in any cluster.  Nodes placed in a
+// cluster are included in the dot output even if they are isolated.
+//
+// Graphviz programs such as dot draw clusters as boxes enclosing their nodes.
+func Clusters(f func(graph.NI) int) func(*Config) {
+	return func(c *Config) { c.Clusters = f }
+}
+
 // Directed specifies whether to write a dot format directected or undirected
 // graph.
 //
@@ -57,6 +86,29 @@ func Directed(d bool) func(*Config) {
 	return func(c *Config) { c.Directed = d }
 }
 
+// EdgeAttr specifies a function to generate dot format attributes for
+// edges.
+//
+// The function is called for each edge written, with the from and to nodes
+// and the arc label.  For unlabeled graph types the label argument is 0.
+// Returned attributes are written as an attribute list on the edge
+// statement.  For labeled graph types they follow the label attribute
+// generated by the EdgeLabel function.  Values are written as quoted
+// strings, for example color = "light blue".
+//
+// For undirected output, reciprocal arcs are written as a single edge and
+// the function is called once for the edge with the node pair in canonical
+// order, fr <= to.  Attributes thus apply to the edge regardless of which
+// of the reciprocal arcs is written.
+//
+// When EdgeAttr is specified, arcs of unlabeled graphs are written as
+// separate edge statements rather than combined in a subgraph.
+//
+// See also PenWidthByWeight.
+func EdgeAttr(f func(fr, to graph.NI, l graph.LI) []AttrKV) func(*Config) {
+	return func(c *Config) { c.EdgeAttr = f }
+}
+
 // EdgeLabel specifies a function to generate edge label strings for the
 // dot format given the arc label integers of graph package.
 //
@@ -99,6 +151,18 @@ func Isolated(i bool) func(*Config) {
 	return func(c *Config) { c.Isolated = i }
 }
 
+// NodeAttr specifies a function to generate dot format attributes for
+// nodes.
+//
+// The function is called for each node of the graph.  For nodes where it
+// returns a non-empty list, a node statement with the attribute list is
+// written before edge statements.  Such nodes are then included in the dot
+// output even if they are isolated.  As with EdgeAttr, values are written
+// as quoted strings.
+func NodeAttr(f func(graph.NI) []AttrKV) func(*Config) {
+	return func(c *Config) { c.NodeAttr = f }
+}
+
 // NodeID specifies a function to generate node ID strings for the
 // dot format given the node integers of graph package.
 //
@@ -108,6 +172,22 @@ func NodeID(f func(graph.NI) string) func(*Config) {
 	return func(c *Config) { c.NodeID = f }
 }
 
+// NodeOrder specifies the order for writing nodes.
+//
+// Statements for nodes, and for arcs from nodes, are written in the order
+// of the node list given.  Nodes not in the list are then written in
+// increasing order.  Node numbers out of range for the graph and repeated
+// node numbers are ignored.  The default order is simply increasing node
+// number.
+//
+// For example a topological ordering of a DAG can give dot output with
+// statements in the same order as the layout.  The node order affects only
+// the order of statements, not the graph written.  It has no effect on the
+// order of edges of a FromList or WeightedEdgeList.
+func NodeOrder(o []graph.NI) func(*Config) {
+	return func(c *Config) { c.NodeOrder = o }
+}
+
 // NodePos specifies a function to format coordinate strings.
 //
 // The resulting dot file should be rendered with Graphviz programs
@@ -116,6 +196,26 @@ func NodePos(f func(graph.NI) string) func(*Config) {
 	return func(c *Config) { c.NodePos = f }
 }
 
+// PenWidthByWeight adds a penwidth attribute to edges, computed from arc
+// weights.
+//
+// The penwidth of each edge is w(l) * scale where l is the arc label.  For
+// unlabeled graph types l is 0.  The penwidth attribute is added to any
+// attributes of an EdgeAttr function specified before PenWidthByWeight.
+// An EdgeAttr function specified after PenWidthByWeight replaces it.
+func PenWidthByWeight(w func(graph.LI) float64, scale float64) func(*Config) {
+	return func(c *Config) {
+		ea := c.EdgeAttr
+		c.EdgeAttr = func(fr, to graph.NI, l graph.LI) []AttrKV {
+			var a []AttrKV
+			if ea != nil {
+				a = ea(fr, to, l)
+			}
+			return append(a, AttrKV{"penwidth", fmt.Sprintf("%g", w(l)*scale)})
+		}
+	}
+}
+
 // UndirectArcs, for the WeightedEdgeList graph type, specifies to write
 // each element of the edge list as a dot file undirected edge.
 //
@@ -11,6 +11,42 @@ import (
 	"github.com/soniakeys/graph/dot"
 )
 
+func ExampleClusters() {
+	// strongly connected components as clusters
+	//
+	//   0<-->1--->2<-->3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {0, 2},
+		2: {3},
+		3: {2},
+	}}
+	c := make([]int, len(g.AdjacencyList))
+	for i, scc := range g.Kosaraju() {
+		for _, n := range scc {
+			c[n] = i
+		}
+	}
+	dot.Write(g, os.Stdout, dot.Clusters(func(n graph.NI) int {
+		return c[n]
+	}))
+	// Output:
+	// digraph {
+	//   subgraph cluster_0 {
+	//     2
+	//     3
+	//   }
+	//   subgraph cluster_1 {
+	//     0
+	//     1
+	//   }
+	//   0 -> 1
+	//   1 -> {0 2}
+	//   2 -> 3
+	//   3 -> 2
+	// }
+}
+
 func ExampleDirected() {
 	// arcs directed down:
 	// 0  2
@@ -50,6 +86,74 @@ func ExampleDirected() {
 	// }
 }
 
+func ExampleEdgeAttr() {
+	// arcs directed down:
+	// 0  2
+	// | /|
+	// |/ |
+	// 3  4
+	g := graph.AdjacencyList{
+		0: {3},
+		2: {3, 4},
+		4: {},
+	}
+	dot.Write(g, os.Stdout, dot.EdgeAttr(func(fr, to graph.NI, l graph.LI) []dot.AttrKV {
+		if fr == 2 && to == 3 {
+			return []dot.AttrKV{{"color", "light blue"}, {"style", "dashed"}}
+		}
+		return nil
+	}))
+	fmt.Println()
+
+	// labeled graph, attributes follow the label
+	lg := graph.LabeledAdjacencyList{
+		0: {{To: 3, Label: 7}},
+		2: {{To: 3, Label: 9}},
+	}
+	dot.Write(lg, os.Stdout, dot.EdgeAttr(func(fr, to graph.NI, l graph.LI) []dot.AttrKV {
+		if l > 8 {
+			return []dot.AttrKV{{"penwidth", "3"}}
+		}
+		return nil
+	}))
+	// Output:
+	// digraph {
+	//   0 -> 3
+	//   2 -> 3 [color = "light blue", style = "dashed"]
+	//   2 -> 4
+	// }
+	// digraph {
+	//   0 -> 3 [label = 7]
+	//   2 -> 3 [label = 9, penwidth = "3"]
+	// }
+}
+
+func ExampleEdgeAttr_undirected() {
+	//   0---1
+	//   |  /
+	//   | /
+	//   2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 0)
+	g.AddEdge(1, 2)
+	// edges are identified by node pairs in canonical order, even where
+	// NodeOrder causes edge 0--2 to be written as 2--0.
+	dot.Write(g, os.Stdout, dot.NodeOrder([]graph.NI{2}),
+		dot.EdgeAttr(func(fr, to graph.NI, l graph.LI) []dot.AttrKV {
+			if fr == 0 && to == 2 {
+				return []dot.AttrKV{{"color", "red"}}
+			}
+			return nil
+		}))
+	// Output:
+	// graph {
+	//   2 -- 0 [color = "red"]
+	//   2 -- 1
+	//   0 -- 1
+	// }
+}
+
 func ExampleEdgeLabel() {
 	// arcs directed down:
 	//      0       4
@@ -131,6 +235,58 @@ func ExampleIsolated() {
 	// }
 }
 
+func ExampleNodeAttr() {
+	// arcs directed down:
+	// 0  2
+	// | /|
+	// |/ |
+	// 3  4
+	g := graph.AdjacencyList{
+		0: {3},
+		2: {3, 4},
+		4: {},
+	}
+	dot.Write(g, os.Stdout, dot.NodeAttr(func(n graph.NI) []dot.AttrKV {
+		if n == 3 {
+			return []dot.AttrKV{{"shape", "box"}, {"label", "sink"}}
+		}
+		return nil
+	}))
+	// Output:
+	// digraph {
+	//   3 [shape = "box", label = "sink"]
+	//   0 -> 3
+	//   2 -> {3 4}
+	// }
+}
+
+func ExampleNodeOrder() {
+	//   3-->0-->2
+	//   |       ^
+	//   +-->1---+
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2},
+		1: {2},
+		3: {0, 1},
+	}}
+	ord, _ := g.Topological()
+	dot.Write(g, os.Stdout, dot.NodeOrder(ord))
+	fmt.Println()
+	// nodes not listed follow in increasing order
+	dot.Write(g, os.Stdout, dot.NodeOrder([]graph.NI{1}))
+	// Output:
+	// digraph {
+	//   3 -> {0 1}
+	//   1 -> 2
+	//   0 -> 2
+	// }
+	// digraph {
+	//   1 -> 2
+	//   0 -> 2
+	//   3 -> {0 1}
+	// }
+}
+
 func ExampleNodePos() {
 	// 0--1
 	// |\
@@ -227,6 +383,29 @@ func ExampleNodeID_construction() {
 	// }
 }
 
+func ExamplePenWidthByWeight() {
+	//      (1.5)
+	//    0-------1
+	//     \     /
+	// (.5) \   / (3)
+	//       \ /
+	//        2
+	w := []float64{1.5, .5, 3}
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{0, 2}, 1)
+	g.AddEdge(graph.Edge{1, 2}, 2)
+	dot.Write(g, os.Stdout, dot.PenWidthByWeight(func(l graph.LI) float64 {
+		return w[l]
+	}, 2))
+	// Output:
+	// graph {
+	//   0 -- 1 [label = 0, penwidth = "3"]
+	//   0 -- 2 [label = 1, penwidth = "1"]
+	//   1 -- 2 [label = 2, penwidth = "6"]
+	// }
+}
+
 func ExampleUndirectArcs() {
 	//              (label 0, wt 1.6)
 	//          0----------------------2
@@ -0,0 +1,275 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package dot
+
+// read.go has a reader for a simple subset of the dot format.
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadAdjacencyList reads a graph in a simple subset of the dot format.
+//
+// The subset is that written by Write for unlabeled graphs.  The input
+// must be a single graph or digraph, optionally named.  Statements within
+// the graph may be node statements consisting of a single node ID or edge
+// statements of the form a -> b or a -> {b c ...}, using -- for an undirected
+// graph.  Statements may be separated by newlines or semicolons.  IDs may be
+// unquoted or double quoted.  Graph attribute statements of the form
+// attr = val are accepted and ignored.  Attribute lists, attribute
+// statements, subgraph statements, edge chains, and the strict keyword are
+// not supported and produce an error, as do comments.
+//
+// If all node IDs are non-negative integers in the range of graph.NI, they
+// are used as node numbers.  Otherwise nodes are numbered in order of first
+// appearance.  Result labels
+// holds the ID string of each node, or an empty string for node numbers not
+// appearing in the input.
+//
+// For an undirected graph, each edge of the input is returned as a pair
+// of reciprocal arcs, or a single arc for a loop.
+func ReadAdjacencyList(r io.Reader) (g graph.AdjacencyList, labels []string, err error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	toks, err := tokenize(string(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &parser{toks: toks, ids: map[string]int{}}
+	if err = p.parse(); err != nil {
+		return nil, nil, err
+	}
+	// assign node numbers
+	num := make([]graph.NI, len(p.names))
+	order := len(p.names)
+	numeric := true
+	for i, s := range p.names {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n < 0 || strconv.FormatInt(n, 10) != s {
+			numeric = false
+			break
+		}
+		num[i] = graph.NI(n)
+	}
+	if numeric {
+		order = 0
+		for _, n := range num {
+			if int(n) >= order {
+				order = int(n) + 1
+			}
+		}
+	} else {
+		for i := range num {
+			num[i] = graph.NI(i)
+		}
+	}
+	g = make(graph.AdjacencyList, order)
+	labels = make([]string, order)
+	for i, s := range p.names {
+		labels[num[i]] = s
+	}
+	for _, a := range p.arcs {
+		fr, to := num[a[0]], num[a[1]]
+		g[fr] = append(g[fr], to)
+		if !p.directed && fr != to {
+			g[to] = append(g[to], fr)
+		}
+	}
+	return g, labels, nil
+}
+
+// token is a lexical token of the dot format.  For quoted IDs, s is the
+// unquoted string.
+type token struct {
+	s      string
+	id     bool // true for an ID, false for punctuation
+	quoted bool
+}
+
+func tokenize(s string) (toks []token, err error) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '{' || c == '}' || c == ';' || c == '=' || c == '[' ||
+			c == ']' || c == ',':
+			toks = append(toks, token{s: s[i : i+1]})
+			i++
+		case strings.HasPrefix(s[i:], "->") || strings.HasPrefix(s[i:], "--"):
+			toks = append(toks, token{s: s[i : i+2]})
+			i += 2
+		case c == '/' || c == '#':
+			return nil, fmt.Errorf("dot: comments not supported")
+		case c == '"':
+			var q []byte
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) && s[j+1] == '"' {
+					j++
+				}
+				q = append(q, s[j])
+			}
+			if j == len(s) {
+				return nil, fmt.Errorf("dot: unterminated string")
+			}
+			toks = append(toks, token{s: string(q), id: true, quoted: true})
+			i = j + 1
+		case isIDChar(rune(c)) || c >= 0x80:
+			j := i
+			for j < len(s) && (isIDChar(rune(s[j])) || s[j] >= 0x80) {
+				j++
+			}
+			toks = append(toks, token{s: s[i:j], id: true})
+			i = j
+		default:
+			return nil, fmt.Errorf("dot: unexpected character %q", c)
+		}
+	}
+	return
+}
+
+func isIDChar(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type parser struct {
+	toks     []token
+	directed bool
+	ids      map[string]int // node index by ID
+	names    []string       // node IDs by index
+	arcs     [][2]int       // arcs as pairs of node indexes
+}
+
+// next returns the next token, or a token with an empty string at the end
+// of input.
+func (p *parser) next() token {
+	if len(p.toks) == 0 {
+		return token{}
+	}
+	t := p.toks[0]
+	p.toks = p.toks[1:]
+	return t
+}
+
+func (p *parser) peek() token {
+	if len(p.toks) == 0 {
+		return token{}
+	}
+	return p.toks[0]
+}
+
+// keyword returns true if t is an unquoted ID matching the dot keyword kw.
+func keyword(t token, kw string) bool {
+	return t.id && !t.quoted && strings.EqualFold(t.s, kw)
+}
+
+func (p *parser) node(t token) int {
+	x, ok := p.ids[t.s]
+	if !ok {
+		x = len(p.names)
+		p.ids[t.s] = x
+		p.names = append(p.names, t.s)
+	}
+	return x
+}
+
+func (p *parser) parse() error {
+	t := p.next()
+	switch {
+	case keyword(t, "digraph"):
+		p.directed = true
+	case keyword(t, "graph"):
+	case keyword(t, "strict"):
+		return fmt.Errorf("dot: strict not supported")
+	default:
+		return fmt.Errorf("dot: expected graph or digraph")
+	}
+	if t = p.next(); t.id { // optional graph ID
+		t = p.next()
+	}
+	if t.s != "{" || t.id {
+		return fmt.Errorf("dot: expected {")
+	}
+	op, kind := "--", "graph"
+	if p.directed {
+		op, kind = "->", "digraph"
+	}
+	for {
+		t = p.next()
+		switch {
+		case t.s == "" && !t.id:
+			return fmt.Errorf("dot: unexpected end of input")
+		case !t.id && t.s == "}":
+			if len(p.toks) > 0 {
+				return fmt.Errorf("dot: unexpected input after graph")
+			}
+			return nil
+		case !t.id && t.s == ";":
+			continue
+		case !t.id:
+			return fmt.Errorf("dot: unexpected %q", t.s)
+		case keyword(t, "subgraph"):
+			return fmt.Errorf("dot: subgraph not supported")
+		case keyword(t, "node") || keyword(t, "edge") || keyword(t, "graph"):
+			return fmt.Errorf("dot: attribute statements not supported")
+		}
+		// t is an ID starting a statement
+		switch nt := p.peek(); {
+		case nt.id || nt.s == "" || nt.s == ";" || nt.s == "}":
+			p.node(t) // node statement
+			continue
+		case nt.s == "=": // graph attribute, ignored
+			p.next()
+			if !p.next().id {
+				return fmt.Errorf("dot: expected attribute value")
+			}
+			continue
+		case nt.s == "[":
+			return fmt.Errorf("dot: attribute lists not supported")
+		case nt.s == "->" || nt.s == "--":
+			if nt.s != op {
+				return fmt.Errorf("dot: edge operator %s in %s", nt.s, kind)
+			}
+			p.next()
+		default:
+			return fmt.Errorf("dot: unexpected %q", nt.s)
+		}
+		// edge statement.  t is the from node.
+		fr := p.node(t)
+		rhs := p.next()
+		switch {
+		case rhs.id:
+			p.arcs = append(p.arcs, [2]int{fr, p.node(rhs)})
+		case rhs.s == "{":
+			for {
+				to := p.next()
+				if !to.id {
+					if to.s != "}" {
+						return fmt.Errorf("dot: expected node ID or }")
+					}
+					break
+				}
+				p.arcs = append(p.arcs, [2]int{fr, p.node(to)})
+			}
+		default:
+			return fmt.Errorf("dot: expected node ID or {")
+		}
+		switch nt := p.peek(); {
+		case !nt.id && (nt.s == "->" || nt.s == "--"):
+			return fmt.Errorf("dot: edge chains not supported")
+		case nt.s == "[" && !nt.id:
+			return fmt.Errorf("dot: attribute lists not supported")
+		}
+	}
+}
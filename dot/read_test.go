@@ -0,0 +1,126 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package dot_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/dot"
+)
+
+func ExampleReadAdjacencyList() {
+	g, labels, err := dot.ReadAdjacencyList(strings.NewReader(`digraph {
+  0 -> {1 2}
+  1 -> 2; 2 -> 3
+}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	fmt.Printf("%q\n", labels)
+	// Output:
+	// 0 [1 2]
+	// 1 [2]
+	// 2 [3]
+	// 3 []
+	// ["0" "1" "2" "3"]
+}
+
+func ExampleReadAdjacencyList_names() {
+	g, labels, err := dot.ReadAdjacencyList(strings.NewReader(
+		`graph G { a -- b; b -- "c d"; e }`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, labels[fr], to)
+	}
+	// Output:
+	// 0 a [1]
+	// 1 b [0 2]
+	// 2 c d [1]
+	// 3 e []
+}
+
+func ExampleReadAdjacencyList_attributes() {
+	_, _, err := dot.ReadAdjacencyList(strings.NewReader(
+		`digraph { 0 -> 1 [label = 3] }`))
+	fmt.Println(err)
+	// Output:
+	// dot: attribute lists not supported
+}
+
+func TestReadAdjacencyList(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(10)
+		g := make(graph.AdjacencyList, n)
+		for m := r.Intn(3 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.NI(r.Intn(n)))
+		}
+		// Isolated(true) so the result has the same order
+		s, err := dot.String(g, dot.Isolated(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := dot.ReadAdjacencyList(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// trailing isolated nodes may be lost, but arcs must round trip
+		for fr, to := range g {
+			var gt []graph.NI
+			if fr < len(got) {
+				gt = got[fr]
+			}
+			if !sameMultiset(to, gt) {
+				t.Fatal(s, "node", fr, "want", to, "got", gt)
+			}
+		}
+	}
+}
+
+func TestReadAdjacencyList_largeID(t *testing.T) {
+	// IDs beyond the range of graph.NI are read as names.
+	for _, s := range []string{
+		"digraph { 3000000000 }",
+		"digraph { 0 -> 2147483648 }",
+	} {
+		g, labels, err := dot.ReadAdjacencyList(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(s, err)
+		}
+		if len(g) != len(labels) || len(g) > 2 {
+			t.Fatal(s, "got", g, labels)
+		}
+	}
+}
+
+func sameMultiset(a, b []graph.NI) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	c := map[graph.NI]int{}
+	for _, n := range a {
+		c[n]++
+	}
+	for _, n := range b {
+		c[n]--
+	}
+	for _, x := range c {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
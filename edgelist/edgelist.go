@@ -0,0 +1,182 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+// Package edgelist reads and writes graphs from package graph as plain text
+// edge lists.
+//
+// An edge list has one arc or edge per line, given as whitespace separated
+// from and to node numbers, and for labeled graphs a third column with the
+// label.  Blank lines and lines starting with # are ignored.  This is the
+// format of many published datasets, the SNAP collection for example.
+//
+// Node numbers are used directly as node numbers of the result, so memory
+// is proportional to the largest node number, not the number of lines.
+// A single line such as "0 2000000000" allocates an adjacency list of two
+// billion nodes.  Datasets with sparse node IDs, as some SNAP datasets have,
+// should be renumbered densely before reading.
+//
+// Like package dot, edgelist is a separate package from graph.  Graph knows
+// nothing of edge lists.
+//
+// An edge list does not record the order of a graph.  The readers return
+// graphs with order one more than the maximum node number read.  Isolated
+// nodes with numbers above the maximum are not recovered.
+package edgelist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadAdjacencyList reads an edge list of node number pairs.
+//
+// If directed is true, each line gives an arc.  If directed is false, each
+// line gives an undirected edge, returned as a pair of reciprocal arcs or
+// a single arc for a loop.  Lines must have exactly two columns.
+func ReadAdjacencyList(r io.Reader, directed bool) (graph.AdjacencyList, error) {
+	var g graph.AdjacencyList
+	err := read(r, 2, func(fr, to graph.NI, _ graph.LI) {
+		if n := maxNI(fr, to); int(n) >= len(g) {
+			g = append(g, make(graph.AdjacencyList, int(n)+1-len(g))...)
+		}
+		g[fr] = append(g[fr], to)
+		if !directed && fr != to {
+			g[to] = append(g[to], fr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ReadLabeledAdjacencyList reads an edge list of node number pairs with
+// integer labels.
+//
+// Lines must have exactly three columns, the third being the arc or edge
+// label.  Otherwise it is as described for ReadAdjacencyList.
+func ReadLabeledAdjacencyList(r io.Reader, directed bool) (graph.LabeledAdjacencyList, error) {
+	var g graph.LabeledAdjacencyList
+	err := read(r, 3, func(fr, to graph.NI, l graph.LI) {
+		if n := maxNI(fr, to); int(n) >= len(g) {
+			g = append(g, make(graph.LabeledAdjacencyList, int(n)+1-len(g))...)
+		}
+		g[fr] = append(g[fr], graph.Half{To: to, Label: l})
+		if !directed && fr != to {
+			g[to] = append(g[to], graph.Half{To: fr, Label: l})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func maxNI(a, b graph.NI) graph.NI {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// read parses lines of cols columns, calling f for each.
+func read(r io.Reader, cols int, f func(fr, to graph.NI, l graph.LI)) error {
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		t := strings.TrimSpace(s.Text())
+		if t == "" || t[0] == '#' {
+			continue
+		}
+		fs := strings.Fields(t)
+		if len(fs) != cols {
+			return fmt.Errorf("edgelist: line %d: %d columns, want %d",
+				line, len(fs), cols)
+		}
+		var v [3]int64
+		for i, c := range fs {
+			x, err := strconv.ParseInt(c, 10, 32)
+			if err != nil {
+				return fmt.Errorf("edgelist: line %d: %v", line, err)
+			}
+			if i < 2 && x < 0 {
+				return fmt.Errorf("edgelist: line %d: negative node %d",
+					line, x)
+			}
+			v[i] = x
+		}
+		f(graph.NI(v[0]), graph.NI(v[1]), graph.LI(v[2]))
+	}
+	return s.Err()
+}
+
+// WriteAdjacencyList writes each arc of g as a line of from and to node
+// numbers separated by a tab.
+func WriteAdjacencyList(g graph.AdjacencyList, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	for fr, to := range g {
+		for _, to := range to {
+			if _, err := fmt.Fprintf(b, "%d\t%d\n", fr, to); err != nil {
+				return err
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// WriteUndirected writes each edge of g once, as a line of node numbers
+// separated by a tab, smaller node number first.
+//
+// Reading the result with ReadAdjacencyList with directed false recovers
+// the arcs of g, although possibly in a different order.
+func WriteUndirected(g graph.Undirected, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if graph.NI(fr) > to {
+				continue
+			}
+			if _, err := fmt.Fprintf(b, "%d\t%d\n", fr, to); err != nil {
+				return err
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// WriteLabeledAdjacencyList writes each arc of g as a line of from node,
+// to node, and label separated by tabs.
+func WriteLabeledAdjacencyList(g graph.LabeledAdjacencyList, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	for fr, to := range g {
+		for _, h := range to {
+			_, err := fmt.Fprintf(b, "%d\t%d\t%d\n", fr, h.To, h.Label)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// WriteLabeledUndirected writes each edge of g once, as a line of node
+// numbers and label separated by tabs, smaller node number first.
+func WriteLabeledUndirected(g graph.LabeledUndirected, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, h := range to {
+			if graph.NI(fr) > h.To {
+				continue
+			}
+			_, err := fmt.Fprintf(b, "%d\t%d\t%d\n", fr, h.To, h.Label)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return b.Flush()
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package edgelist_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/edgelist"
+)
+
+func ExampleReadAdjacencyList() {
+	g, err := edgelist.ReadAdjacencyList(strings.NewReader(`# a small digraph
+0 1
+0	2
+
+1 2
+3 3
+`), true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 2]
+	// 1 [2]
+	// 2 []
+	// 3 [3]
+}
+
+func ExampleReadAdjacencyList_undirected() {
+	g, err := edgelist.ReadAdjacencyList(strings.NewReader(`0 1
+1 2
+`), false)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [0 2]
+	// 2 [1]
+}
+
+func ExampleReadLabeledAdjacencyList() {
+	g, err := edgelist.ReadLabeledAdjacencyList(strings.NewReader(`0 1 30
+1 2 -4
+`), true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [{1 30}]
+	// 1 [{2 -4}]
+	// 2 []
+}
+
+func ExampleWriteAdjacencyList() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {},
+	}
+	edgelist.WriteAdjacencyList(g, os.Stdout)
+	// Output:
+	// 0	1
+	// 0	2
+	// 1	2
+}
+
+func ExampleWriteUndirected() {
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 1)
+	g.AddEdge(2, 2)
+	edgelist.WriteUndirected(g, os.Stdout)
+	// Output:
+	// 0	1
+	// 1	2
+	// 2	2
+}
+
+func ExampleWriteLabeledAdjacencyList() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 30}},
+		1: {{To: 2, Label: -4}},
+		2: {},
+	}
+	edgelist.WriteLabeledAdjacencyList(g, os.Stdout)
+	// Output:
+	// 0	1	30
+	// 1	2	-4
+}
+
+func ExampleWriteLabeledUndirected() {
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 30)
+	g.AddEdge(graph.Edge{2, 1}, -4)
+	edgelist.WriteLabeledUndirected(g, os.Stdout)
+	// Output:
+	// 0	1	30
+	// 1	2	-4
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, s := range []string{
+		"0\n",
+		"0 1 2\n",
+		"0 x\n",
+		"-1 0\n",
+		"0 99999999999\n",
+	} {
+		if g, err := edgelist.ReadAdjacencyList(strings.NewReader(s), true); err == nil {
+			t.Fatalf("%q accepted as %v", s, g)
+		}
+	}
+	for _, s := range []string{
+		"0 1\n",
+		"0 1 2 3\n",
+		"0 1 x\n",
+	} {
+		if g, err := edgelist.ReadLabeledAdjacencyList(strings.NewReader(s), true); err == nil {
+			t.Fatalf("%q accepted as %v", s, g)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(16))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(20)
+		var g graph.Undirected
+		var lg graph.LabeledUndirected
+		for j := r.Intn(3 * n); j > 0; j-- {
+			e := graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}
+			g.AddEdge(e.N1, e.N2)
+			lg.AddEdge(e, graph.LI(r.Intn(100)))
+		}
+		// directed
+		var b bytes.Buffer
+		if err := edgelist.WriteAdjacencyList(g.AdjacencyList, &b); err != nil {
+			t.Fatal(err)
+		}
+		h, err := edgelist.ReadAdjacencyList(&b, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.AdjacencyList.Equal(h) {
+			t.Fatal(g, h)
+		}
+		// undirected
+		b.Reset()
+		if err := edgelist.WriteUndirected(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		if h, err = edgelist.ReadAdjacencyList(&b, false); err != nil {
+			t.Fatal(err)
+		}
+		if !g.AdjacencyList.EqualUnordered(h) {
+			t.Fatal(g, h)
+		}
+		// labeled
+		b.Reset()
+		if err := edgelist.WriteLabeledAdjacencyList(lg.LabeledAdjacencyList, &b); err != nil {
+			t.Fatal(err)
+		}
+		lh, err := edgelist.ReadLabeledAdjacencyList(&b, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !lg.LabeledAdjacencyList.Equal(lh) {
+			t.Fatal(lg, lh)
+		}
+		b.Reset()
+		if err := edgelist.WriteLabeledUndirected(lg, &b); err != nil {
+			t.Fatal(err)
+		}
+		if lh, err = edgelist.ReadLabeledAdjacencyList(&b, false); err != nil {
+			t.Fatal(err)
+		}
+		if !lg.LabeledAdjacencyList.EqualUnordered(lh) {
+			t.Fatal(lg, lh)
+		}
+	}
+}
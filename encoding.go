@@ -0,0 +1,334 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// encoding.go has methods for serializing adjacency lists.
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MarshalBinary encodes g in a compact binary format.
+//
+// The encoding is a sequence of unsigned varints: the order of g, then for
+// each node the number of arcs from the node followed by the to-nodes of
+// the arcs.  Empty lists, including trailing ones, are encoded and so
+// preserved by UnmarshalBinary.
+//
+// MarshalBinary implements encoding.BinaryMarshaler.  It returns an error
+// if g has a negative node number.
+func (g AdjacencyList) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, binary.MaxVarintLen64)
+	b = appendUvarint(b, uint64(len(g)))
+	for fr, to := range g {
+		b = appendUvarint(b, uint64(len(to)))
+		for _, to := range to {
+			if to < 0 {
+				return nil, fmt.Errorf("negative to-node %d from node %d", to, fr)
+			}
+			b = appendUvarint(b, uint64(to))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes data as encoded by MarshalBinary, replacing
+// the contents of *g.
+//
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.  It returns an error
+// if data is malformed or has a to-node out of range for the encoded order.
+func (g *AdjacencyList) UnmarshalBinary(data []byte) error {
+	d := varintDecoder{b: data}
+	order := d.count()
+	a := make(AdjacencyList, order)
+	for fr := range a {
+		na := d.count()
+		if d.err != nil {
+			break
+		}
+		to := make([]NI, na)
+		for i := range to {
+			to[i] = d.ni(order)
+		}
+		a[fr] = to
+	}
+	if err := d.done(); err != nil {
+		return err
+	}
+	*g = a
+	return nil
+}
+
+// MarshalBinary encodes g in a compact binary format.
+//
+// The encoding is that of AdjacencyList.MarshalBinary, with each to-node
+// followed by the arc label as a signed varint.
+//
+// MarshalBinary implements encoding.BinaryMarshaler.  It returns an error
+// if g has a negative node number.
+func (g LabeledAdjacencyList) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, binary.MaxVarintLen64)
+	b = appendUvarint(b, uint64(len(g)))
+	for fr, to := range g {
+		b = appendUvarint(b, uint64(len(to)))
+		for _, h := range to {
+			if h.To < 0 {
+				return nil, fmt.Errorf("negative to-node %d from node %d", h.To, fr)
+			}
+			b = appendUvarint(b, uint64(h.To))
+			b = appendVarint(b, int64(h.Label))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes data as encoded by MarshalBinary, replacing
+// the contents of *g.
+//
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.  It returns an error
+// if data is malformed or has a to-node out of range for the encoded order.
+func (g *LabeledAdjacencyList) UnmarshalBinary(data []byte) error {
+	d := varintDecoder{b: data}
+	order := d.count()
+	a := make(LabeledAdjacencyList, order)
+	for fr := range a {
+		na := d.count()
+		if d.err != nil {
+			break
+		}
+		to := make([]Half, na)
+		for i := range to {
+			to[i].To = d.ni(order)
+			to[i].Label = d.li()
+		}
+		a[fr] = to
+	}
+	if err := d.done(); err != nil {
+		return err
+	}
+	*g = a
+	return nil
+}
+
+// adjacencyJSON is the JSON form of an AdjacencyList.
+type adjacencyJSON struct {
+	Order int    `json:"order"`
+	Arcs  [][]NI `json:"arcs"`
+}
+
+// MarshalJSON encodes g as a JSON object with the order of g and
+// the to-nodes of arcs from each node, for example
+//
+//	{"order":3,"arcs":[[1,2],[2],[]]}
+//
+// Empty lists, including trailing ones, are encoded as [].
+//
+// MarshalJSON implements json.Marshaler.
+func (g AdjacencyList) MarshalJSON() ([]byte, error) {
+	j := adjacencyJSON{len(g), make([][]NI, len(g))}
+	for fr, to := range g {
+		if to == nil {
+			to = []NI{}
+		}
+		j.Arcs[fr] = to
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes data as encoded by MarshalJSON, replacing the
+// contents of *g.
+//
+// The result has the encoded order.  The arcs list may be shorter than
+// the order, in which case remaining nodes have no arcs.  UnmarshalJSON
+// implements json.Unmarshaler.  It returns an error if the arcs list is
+// longer than the order, if the order exceeds the length of data, or if a
+// to-node is out of range.
+func (g *AdjacencyList) UnmarshalJSON(data []byte) error {
+	var j adjacencyJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkJSONOrder(j.Order, len(j.Arcs), len(data)); err != nil {
+		return err
+	}
+	a := make(AdjacencyList, j.Order)
+	copy(a, j.Arcs)
+	for fr, to := range a {
+		for _, to := range to {
+			if to < 0 || int(to) >= j.Order {
+				return fmt.Errorf("arc %d->%d out of range for order %d",
+					fr, to, j.Order)
+			}
+		}
+	}
+	*g = a
+	return nil
+}
+
+// halfJSON is the JSON form of a Half.
+type halfJSON struct {
+	To    NI `json:"to"`
+	Label LI `json:"label"`
+}
+
+// labeledAdjacencyJSON is the JSON form of a LabeledAdjacencyList.
+type labeledAdjacencyJSON struct {
+	Order int          `json:"order"`
+	Arcs  [][]halfJSON `json:"arcs"`
+}
+
+// MarshalJSON encodes g as a JSON object with the order of g and
+// the half arcs from each node, for example
+//
+//	{"order":2,"arcs":[[{"to":1,"label":5}],[]]}
+//
+// Empty lists, including trailing ones, are encoded as [].
+//
+// MarshalJSON implements json.Marshaler.
+func (g LabeledAdjacencyList) MarshalJSON() ([]byte, error) {
+	j := labeledAdjacencyJSON{len(g), make([][]halfJSON, len(g))}
+	for fr, to := range g {
+		hs := make([]halfJSON, len(to))
+		for i, h := range to {
+			hs[i] = halfJSON{h.To, h.Label}
+		}
+		j.Arcs[fr] = hs
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes data as encoded by MarshalJSON, replacing the
+// contents of *g.
+//
+// The result has the encoded order.  The arcs list may be shorter than
+// the order, in which case remaining nodes have no arcs.  UnmarshalJSON
+// implements json.Unmarshaler.  It returns an error if the arcs list is
+// longer than the order, if the order exceeds the length of data, or if a
+// to-node is out of range.
+func (g *LabeledAdjacencyList) UnmarshalJSON(data []byte) error {
+	var j labeledAdjacencyJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkJSONOrder(j.Order, len(j.Arcs), len(data)); err != nil {
+		return err
+	}
+	a := make(LabeledAdjacencyList, j.Order)
+	for fr, hs := range j.Arcs {
+		if hs == nil {
+			continue
+		}
+		to := make([]Half, len(hs))
+		for i, h := range hs {
+			if h.To < 0 || int(h.To) >= j.Order {
+				return fmt.Errorf("arc %d->%d out of range for order %d",
+					fr, h.To, j.Order)
+			}
+			to[i] = Half{h.To, h.Label}
+		}
+		a[fr] = to
+	}
+	*g = a
+	return nil
+}
+
+// checkJSONOrder validates a decoded order.  Each node takes at least one
+// byte of encoded data, so an order larger than the data is an error.  This
+// guards against huge allocations from corrupt data.
+func checkJSONOrder(order, nArcs, nData int) error {
+	switch {
+	case order < 0:
+		return fmt.Errorf("negative order %d", order)
+	case order > nData:
+		return fmt.Errorf("order %d exceeds data length %d", order, nData)
+	case nArcs > order:
+		return fmt.Errorf("%d arc lists exceed order %d", nArcs, order)
+	}
+	return nil
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+func appendVarint(b []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], x)]...)
+}
+
+// varintDecoder reads varints from b, recording the first error.
+// After an error, reads return 0.
+type varintDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *varintDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errors.New("malformed varint")
+		return 0
+	}
+	d.b = d.b[n:]
+	return x
+}
+
+func (d *varintDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errors.New("malformed varint")
+		return 0
+	}
+	d.b = d.b[n:]
+	return x
+}
+
+// count reads a length.  Each counted item takes at least one byte, so
+// a count larger than the remaining data is an error.  This guards against
+// huge allocations from corrupt data.
+func (d *varintDecoder) count() int {
+	x := d.uvarint()
+	if d.err == nil && x > uint64(len(d.b)) {
+		d.err = fmt.Errorf("count %d exceeds remaining data", x)
+		return 0
+	}
+	return int(x)
+}
+
+// ni reads a node number, which must be less than order.
+func (d *varintDecoder) ni(order int) NI {
+	x := d.uvarint()
+	if d.err == nil && x >= uint64(order) {
+		d.err = fmt.Errorf("node %d out of range for order %d", x, order)
+		return 0
+	}
+	return NI(x)
+}
+
+func (d *varintDecoder) li() LI {
+	x := d.varint()
+	if d.err == nil && int64(LI(x)) != x {
+		d.err = fmt.Errorf("label %d out of range", x)
+		return 0
+	}
+	return LI(x)
+}
+
+// done returns the recorded error, or an error if data remains.
+func (d *varintDecoder) done() error {
+	if d.err == nil && len(d.b) > 0 {
+		d.err = fmt.Errorf("%d bytes of extra data", len(d.b))
+	}
+	return d.err
+}
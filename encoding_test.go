@@ -0,0 +1,288 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_MarshalBinary() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		3: {},
+	}
+	b, err := g.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("% x\n", b)
+	var h graph.AdjacencyList
+	if err := h.UnmarshalBinary(b); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(h), "nodes")
+	for n, to := range h {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 04 02 01 02 01 02 00 00
+	// 4 nodes
+	// 0 [1 2]
+	// 1 [2]
+	// 2 []
+	// 3 []
+}
+
+func ExampleLabeledAdjacencyList_MarshalBinary() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 7}, {To: 2, Label: -1}},
+		2: {},
+	}
+	b, err := g.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("% x\n", b)
+	var h graph.LabeledAdjacencyList
+	if err := h.UnmarshalBinary(b); err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range h {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 03 02 01 0e 02 01 00 00
+	// 0 [{1 7} {2 -1}]
+	// 1 []
+	// 2 []
+}
+
+func TestMarshalBinary(t *testing.T) {
+	r := rand.New(rand.NewSource(14))
+	for i := 0; i < 100; i++ {
+		n := r.Intn(300)
+		g := make(graph.AdjacencyList, n)
+		lg := make(graph.LabeledAdjacencyList, n)
+		for fr := range g {
+			// leave some lists nil, others empty
+			if r.Intn(3) == 0 {
+				continue
+			}
+			g[fr] = []graph.NI{}
+			lg[fr] = []graph.Half{}
+			for j := r.Intn(5); j > 0; j-- {
+				to := graph.NI(r.Intn(n))
+				g[fr] = append(g[fr], to)
+				lg[fr] = append(lg[fr], graph.Half{to, graph.LI(r.Int31() - 1<<30)})
+			}
+		}
+		b, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var h graph.AdjacencyList
+		if err := h.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if len(h) != n {
+			t.Fatal("order", len(h), "want", n)
+		}
+		for fr, to := range g {
+			if len(to) != len(h[fr]) ||
+				len(to) > 0 && !reflect.DeepEqual(to, h[fr]) {
+				t.Fatal("node", fr, h[fr], "want", to)
+			}
+		}
+		lb, err := lg.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lh graph.LabeledAdjacencyList
+		if err := lh.UnmarshalBinary(lb); err != nil {
+			t.Fatal(err)
+		}
+		if len(lh) != n {
+			t.Fatal("order", len(lh), "want", n)
+		}
+		for fr, to := range lg {
+			if len(to) != len(lh[fr]) ||
+				len(to) > 0 && !reflect.DeepEqual(to, lh[fr]) {
+				t.Fatal("node", fr, lh[fr], "want", to)
+			}
+		}
+		// truncated data must fail
+		if len(b) > 1 {
+			if err := h.UnmarshalBinary(b[:r.Intn(len(b))]); err == nil {
+				t.Fatal("truncated data accepted")
+			}
+		}
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	for _, b := range [][]byte{
+		{},
+		{0x80},             // incomplete varint
+		{1, 1, 1},          // node 1 out of range for order 1
+		{1, 0, 0},          // extra data
+		{0xff, 0xff, 0x7f}, // order exceeds data
+	} {
+		var g graph.AdjacencyList
+		if err := g.UnmarshalBinary(b); err == nil {
+			t.Fatalf("% x accepted as %v", b, g)
+		}
+	}
+	var g graph.AdjacencyList
+	if err := g.UnmarshalBinary([]byte{0}); err != nil || len(g) != 0 {
+		t.Fatal("empty graph", g, err)
+	}
+}
+
+func TestUnmarshalBinary_nodeRange(t *testing.T) {
+	// order 2, node 0 has one arc, to node 2
+	g := graph.AdjacencyList{{1}}
+	if err := g.UnmarshalBinary([]byte{2, 1, 2, 0}); err == nil {
+		t.Fatal("to-node 2 accepted for order 2:", g)
+	}
+	if len(g) != 1 || len(g[0]) != 1 || g[0][0] != 1 {
+		t.Fatal("g modified on error:", g)
+	}
+	// labeled, same with label 0 following the to-node
+	lg := graph.LabeledAdjacencyList{{{To: 1, Label: 3}}}
+	if err := lg.UnmarshalBinary([]byte{2, 1, 2, 0, 0}); err == nil {
+		t.Fatal("to-node 2 accepted for order 2:", lg)
+	}
+	if len(lg) != 1 || len(lg[0]) != 1 || lg[0][0] != (graph.Half{To: 1, Label: 3}) {
+		t.Fatal("lg modified on error:", lg)
+	}
+}
+
+func ExampleAdjacencyList_MarshalJSON() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		3: nil,
+	}
+	b, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(b))
+	var h graph.AdjacencyList
+	if err := json.Unmarshal(b, &h); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(h), "nodes")
+	// Output:
+	// {"order":4,"arcs":[[1,2],[2],[],[]]}
+	// 4 nodes
+}
+
+func ExampleLabeledAdjacencyList_MarshalJSON() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 7}},
+		2: {},
+	}
+	b, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(b))
+	var h graph.LabeledAdjacencyList
+	if err := json.Unmarshal(b, &h); err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range h {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// {"order":3,"arcs":[[{"to":1,"label":7}],[],[]]}
+	// 0 [{1 7}]
+	// 1 []
+	// 2 []
+}
+
+func TestMarshalJSON(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	for i := 0; i < 100; i++ {
+		n := r.Intn(30)
+		g := make(graph.AdjacencyList, n)
+		lg := make(graph.LabeledAdjacencyList, n)
+		for fr := range g {
+			for j := r.Intn(4); j > 0; j-- {
+				to := graph.NI(r.Intn(n))
+				g[fr] = append(g[fr], to)
+				lg[fr] = append(lg[fr], graph.Half{to, graph.LI(r.Intn(100) - 50)})
+			}
+		}
+		b, err := json.Marshal(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var h graph.AdjacencyList
+		if err := json.Unmarshal(b, &h); err != nil {
+			t.Fatal(err)
+		}
+		if len(h) != n || !g.Equal(h) {
+			t.Fatal(string(b), h, "want", g)
+		}
+		lb, err := json.Marshal(lg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lh graph.LabeledAdjacencyList
+		if err := json.Unmarshal(lb, &lh); err != nil {
+			t.Fatal(err)
+		}
+		if len(lh) != n || !lg.Equal(lh) {
+			t.Fatal(string(lb), lh, "want", lg)
+		}
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	for _, s := range []string{
+		`[]`,
+		`{"order":-1,"arcs":[]}`,
+		`{"order":1,"arcs":[[],[]]}`,
+		`{"order":2,"arcs":[[2]]}`,
+		`{"order":2,"arcs":[[-1]]}`,
+		`{"order":1000000000000000,"arcs":[]}`, // order exceeds data
+	} {
+		var g graph.AdjacencyList
+		if err := json.Unmarshal([]byte(s), &g); err == nil {
+			t.Fatal(s, "accepted as", g)
+		}
+	}
+	for _, s := range []string{
+		`{"order":2,"arcs":[[{"to":2,"label":0}]]}`,
+		`{"order":1000000000000000,"arcs":[]}`,
+	} {
+		var g graph.LabeledAdjacencyList
+		if err := json.Unmarshal([]byte(s), &g); err == nil {
+			t.Fatal(s, "accepted as", g)
+		}
+	}
+	// short arcs list is padded to order
+	var h graph.AdjacencyList
+	if err := json.Unmarshal([]byte(`{"order":3,"arcs":[[1]]}`), &h); err != nil ||
+		len(h) != 3 {
+		t.Fatal("padding", h, err)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// flow.go has maximum flow algorithms and supporting residual network.
+
+import "math"
+
+// flowNet is a residual network built from a LabeledDirected graph.
+//
+// Arcs are stored in pairs.  Arc a is a forward arc of the original graph
+// and arc a^1 is its reverse.  res holds residual capacity.  For a forward
+// arc a, flow on the arc is res[a^1].
+type flowNet struct {
+	from []NI      // start node of each arc
+	to   []NI      // node at end of each arc
+	res  []float64 // residual capacity of each arc
+	arcs [][]int   // arcs from each node
+}
+
+func newFlowNet(g LabeledAdjacencyList, capacity WeightFunc) *flowNet {
+	n := &flowNet{arcs: make([][]int, len(g))}
+	for fr, to := range g {
+		for _, h := range to {
+			a := len(n.to)
+			n.from = append(n.from, NI(fr), h.To)
+			n.to = append(n.to, h.To, NI(fr))
+			n.res = append(n.res, capacity(h.Label), 0)
+			n.arcs[fr] = append(n.arcs[fr], a)
+			n.arcs[h.To] = append(n.arcs[h.To], a+1)
+		}
+	}
+	return n
+}
+
+// flow returns flow on each arc of the original graph g, in the form
+// described for MaxFlow.
+func (n *flowNet) flow(g LabeledAdjacencyList) [][]float64 {
+	f := make([][]float64, len(g))
+	a := 0 // forward arcs were created in order of g
+	for fr, to := range g {
+		f[fr] = make([]float64, len(to))
+		for i := range to {
+			f[fr][i] = n.res[a+1]
+			a += 2
+		}
+	}
+	return f
+}
+
+// edmondsKarp saturates the network n with flow from source to sink,
+// returning the amount of flow added.
+func (n *flowNet) edmondsKarp(source, sink NI) (total float64) {
+	if source == sink {
+		return 0
+	}
+	via := make([]int, len(n.arcs)) // arc by which each node is reached
+	for {
+		for i := range via {
+			via[i] = -1
+		}
+		// BFS for shortest augmenting path
+		q := []NI{source}
+	bfs:
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for _, a := range n.arcs[fr] {
+				to := n.to[a]
+				if n.res[a] > 0 && via[to] < 0 && to != source {
+					via[to] = a
+					if to == sink {
+						break bfs
+					}
+					q = append(q, to)
+				}
+			}
+		}
+		if via[sink] < 0 {
+			return
+		}
+		// find bottleneck, then augment
+		b := n.res[via[sink]]
+		for nd := sink; nd != source; nd = n.from[via[nd]] {
+			if r := n.res[via[nd]]; r < b {
+				b = r
+			}
+		}
+		for nd := sink; nd != source; nd = n.from[via[nd]] {
+			a := via[nd]
+			n.res[a] -= b
+			n.res[a^1] += b
+		}
+		total += b
+	}
+}
+
+// MaxFlow finds a maximum flow from source to sink using the Edmonds-Karp
+// algorithm.
+//
+// Arc capacities are given by the capacity function and must be
+// non-negative.  Parallel arcs are allowed and are treated as separate
+// arcs, their capacities effectively summing.
+//
+// Returned is the maximum flow value and flow on each arc of g.  Result
+// flow parallels the adjacency list of g: flow[fr][i] is the flow on arc
+// g.LabeledAdjacencyList[fr][i].  Labels are used only as arguments to
+// capacity and need not be distinct.
+func (g LabeledDirected) MaxFlow(source, sink NI, capacity WeightFunc) (maxFlow float64, flow [][]float64) {
+	n := newFlowNet(g.LabeledAdjacencyList, capacity)
+	maxFlow = n.edmondsKarp(source, sink)
+	return maxFlow, n.flow(g.LabeledAdjacencyList)
+}
+
+// dinic saturates the network n with flow from source to sink using Dinic's
+// algorithm, returning the amount of flow added.
+func (n *flowNet) dinic(source, sink NI) (total float64) {
+	if source == sink {
+		return 0
+	}
+	level := make([]int, len(n.arcs))
+	cur := make([]int, len(n.arcs)) // current arc index for each node
+	// augment finds an augmenting path in the level graph from nd, pushing
+	// at most lim flow.  returned is the flow pushed.
+	var augment func(nd NI, lim float64) float64
+	augment = func(nd NI, lim float64) float64 {
+		if nd == sink {
+			return lim
+		}
+		for arcs := n.arcs[nd]; cur[nd] < len(arcs); cur[nd]++ {
+			a := arcs[cur[nd]]
+			to := n.to[a]
+			if n.res[a] <= 0 || level[to] != level[nd]+1 {
+				continue
+			}
+			b := lim
+			if n.res[a] < b {
+				b = n.res[a]
+			}
+			if p := augment(to, b); p > 0 {
+				n.res[a] -= p
+				n.res[a^1] += p
+				return p
+			}
+		}
+		return 0
+	}
+	for {
+		// BFS to build level graph
+		for i := range level {
+			level[i] = -1
+		}
+		level[source] = 0
+		q := []NI{source}
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for _, a := range n.arcs[fr] {
+				if to := n.to[a]; n.res[a] > 0 && level[to] < 0 {
+					level[to] = level[fr] + 1
+					q = append(q, to)
+				}
+			}
+		}
+		if level[sink] < 0 {
+			return
+		}
+		// blocking flow
+		for i := range cur {
+			cur[i] = 0
+		}
+		for {
+			p := augment(source, math.Inf(1))
+			if p == 0 {
+				break
+			}
+			total += p
+		}
+	}
+}
+
+// MaxFlowDinic finds a maximum flow value from source to sink using Dinic's
+// algorithm.
+//
+// Arguments and the maxFlow result are as for MaxFlow.  Dinic's algorithm
+// pushes blocking flows on BFS level graphs and is generally faster than
+// MaxFlow on dense networks.
+func (g LabeledDirected) MaxFlowDinic(source, sink NI, capacity WeightFunc) (maxFlow float64) {
+	return newFlowNet(g.LabeledAdjacencyList, capacity).dinic(source, sink)
+}
+
+// MinCut finds a minimum cut separating source from sink.
+//
+// MinCut computes a maximum flow, then finds nodes reachable from source
+// in the residual network.  Result sourceSide has bits set for exactly these
+// nodes.  Result cutEdges lists the arcs of g from sourceSide to the rest of
+// the graph.  These arcs are saturated by the flow.  Parallel arcs appear
+// in cutEdges once for each arc.  Result cutValue is the sum of capacities
+// of the cut arcs, equal to the maximum flow value.
+//
+// Arguments are as for MaxFlow.
+func (g LabeledDirected) MinCut(source, sink NI, capacity WeightFunc) (cutValue float64, sourceSide Bits, cutEdges []Edge) {
+	n := newFlowNet(g.LabeledAdjacencyList, capacity)
+	cutValue = n.edmondsKarp(source, sink)
+	sourceSide.SetBit(source, 1)
+	q := []NI{source}
+	for len(q) > 0 {
+		fr := q[0]
+		q = q[1:]
+		for _, a := range n.arcs[fr] {
+			if to := n.to[a]; n.res[a] > 0 && sourceSide.Bit(to) == 0 {
+				sourceSide.SetBit(to, 1)
+				q = append(q, to)
+			}
+		}
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		if sourceSide.Bit(NI(fr)) == 0 {
+			continue
+		}
+		for _, h := range to {
+			if sourceSide.Bit(h.To) == 0 {
+				cutEdges = append(cutEdges, Edge{NI(fr), h.To})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,237 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledDirected_MaxFlow() {
+	// arc labels index capacities
+	//
+	//      (16)    (12)
+	//    0----->1----->3
+	//    |      ^\     |\ (20)
+	//    |   (4)| |(10)| v
+	// (13)\     | v (9)|  5
+	//      ---->2<-----  ^
+	//           |  (7)  /(4)
+	//        (14)----->4
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		1: {{To: 2, Label: 2}, {To: 3, Label: 3}},
+		2: {{To: 1, Label: 4}, {To: 4, Label: 5}},
+		3: {{To: 2, Label: 6}, {To: 5, Label: 7}},
+		4: {{To: 3, Label: 8}, {To: 5, Label: 9}},
+		5: {},
+	}}
+	c := []float64{16, 13, 10, 12, 4, 14, 9, 20, 7, 4}
+	f, flow := g.MaxFlow(0, 5, func(l graph.LI) float64 { return c[l] })
+	fmt.Println("max flow:", f)
+	// arcs into sink 5 are 3->5 and 4->5
+	fmt.Println("into sink:", flow[3][1]+flow[4][1])
+	// Output:
+	// max flow: 23
+	// into sink: 23
+}
+
+func ExampleLabeledDirected_MaxFlowDinic() {
+	//      (3)     (2)
+	//    0----->1----->3
+	//    |      |      ^
+	// (2)|   (5)|      |(3)
+	//    v      v      |
+	//    2----->4------
+	//      (1)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 2}, {To: 4, Label: 5}},
+		2: {{To: 4, Label: 1}},
+		4: {{To: 3, Label: 3}},
+		3: {},
+	}}
+	// labels are capacities
+	fmt.Println(g.MaxFlowDinic(0, 3, func(l graph.LI) float64 {
+		return float64(l)
+	}))
+	// Output:
+	// 4
+}
+
+func ExampleLabeledDirected_MinCut() {
+	//      (3)     (2)
+	//    0----->1----->3
+	//    |      |      ^
+	// (2)|   (5)|      |(3)
+	//    v      v      |
+	//    2----->4------
+	//      (1)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 2}, {To: 4, Label: 5}},
+		2: {{To: 4, Label: 1}},
+		4: {{To: 3, Label: 3}},
+		3: {},
+	}}
+	// labels are capacities
+	v, s, e := g.MinCut(0, 3, func(l graph.LI) float64 { return float64(l) })
+	fmt.Println("cut value:", v)
+	fmt.Println("source side:", s.Slice())
+	fmt.Println("cut arcs:", e)
+	// Output:
+	// cut value: 4
+	// source side: [0 2]
+	// cut arcs: [{0 1} {2 4}]
+}
+
+// random flow networks for testing.  labels index capacities.
+func randomFlowNet(r *rand.Rand) (graph.LabeledDirected, []float64) {
+	n := 2 + r.Intn(9)
+	g := make(graph.LabeledAdjacencyList, n)
+	var c []float64
+	for i := n * 2; i > 0; i-- {
+		fr := r.Intn(n)
+		g[fr] = append(g[fr], graph.Half{
+			To:    graph.NI(r.Intn(n)),
+			Label: graph.LI(len(c))})
+		c = append(c, float64(r.Intn(10)))
+	}
+	return graph.LabeledDirected{g}, c
+}
+
+// bruteMinCut enumerates all partitions.
+func bruteMinCut(g graph.LabeledDirected, c []float64, s, t graph.NI) float64 {
+	best := math.Inf(1)
+	for m := 0; m < 1<<uint(len(g.LabeledAdjacencyList)); m++ {
+		in := func(n graph.NI) bool { return m>>uint(n)&1 == 1 }
+		if !in(s) || in(t) {
+			continue
+		}
+		cut := 0.
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, h := range to {
+				if in(graph.NI(fr)) && !in(h.To) {
+					cut += c[h.Label]
+				}
+			}
+		}
+		if cut < best {
+			best = cut
+		}
+	}
+	return best
+}
+
+func TestMaxFlow(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		g, c := randomFlowNet(r)
+		a := g.LabeledAdjacencyList
+		s := graph.NI(0)
+		sink := graph.NI(len(a) - 1)
+		capacity := func(l graph.LI) float64 { return c[l] }
+		f, flow := g.MaxFlow(s, sink, capacity)
+		want := bruteMinCut(g, c, s, sink)
+		if f != want {
+			t.Fatal("max flow", f, "min cut", want)
+		}
+		if d := g.MaxFlowDinic(s, sink, capacity); d != want {
+			t.Fatal("Dinic max flow", d, "min cut", want)
+		}
+		cv, ss, ce := g.MinCut(s, sink, capacity)
+		if cv != want {
+			t.Fatal("MinCut value", cv, "want", want)
+		}
+		if ss.Bit(s) != 1 || ss.Bit(sink) != 0 {
+			t.Fatal("MinCut source side", ss.Slice())
+		}
+		sum := 0.
+		for _, e := range ce {
+			if ss.Bit(e.N1) != 1 || ss.Bit(e.N2) != 0 {
+				t.Fatal("MinCut arc", e, "does not cross cut")
+			}
+		}
+		for fr, to := range a {
+			for _, h := range to {
+				if ss.Bit(graph.NI(fr)) == 1 && ss.Bit(h.To) == 0 {
+					sum += c[h.Label]
+				}
+			}
+		}
+		if sum != want {
+			t.Fatal("MinCut arcs sum", sum, "want", want)
+		}
+		// capacity and conservation
+		net := make([]float64, len(a))
+		for fr, to := range a {
+			for i, h := range to {
+				fl := flow[fr][i]
+				if fl < 0 || fl > c[h.Label] {
+					t.Fatal("arc", fr, h.To, "flow", fl, "capacity", c[h.Label])
+				}
+				net[fr] -= fl
+				net[h.To] += fl
+			}
+		}
+		for n, x := range net {
+			switch graph.NI(n) {
+			case s:
+				x = -x
+				fallthrough
+			case sink:
+				if x != f {
+					t.Fatal("node", n, "net flow", x, "want", f)
+				}
+			default:
+				if x != 0 {
+					t.Fatal("node", n, "not conserved", x)
+				}
+			}
+		}
+	}
+}
+
+func TestMaxFlow_sharedLabels(t *testing.T) {
+	// labels are capacities, several arcs share a label, and one label
+	// is negative, mapped to capacity 0.
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}, {To: 3, Label: -1}},
+		1: {{To: 3, Label: 2}, {To: 4, Label: 5}},
+		2: {{To: 4, Label: 1}},
+		4: {{To: 3, Label: 3}},
+		3: {},
+	}}
+	capacity := func(l graph.LI) float64 {
+		if l < 0 {
+			return 0
+		}
+		return float64(l)
+	}
+	f, flow := g.MaxFlow(0, 3, capacity)
+	if f != 4 {
+		t.Fatal("max flow", f, "want 4")
+	}
+	net := make([]float64, len(flow))
+	for fr, to := range g.LabeledAdjacencyList {
+		if len(flow[fr]) != len(to) {
+			t.Fatal("node", fr, "flows", flow[fr])
+		}
+		for i, h := range to {
+			fl := flow[fr][i]
+			if fl < 0 || fl > capacity(h.Label) {
+				t.Fatal("arc", fr, h.To, "flow", fl)
+			}
+			net[fr] -= fl
+			net[h.To] += fl
+		}
+	}
+	if net[0] != -4 || net[3] != 4 || net[1] != 0 || net[2] != 0 || net[4] != 0 {
+		t.Fatal("net flows", net)
+	}
+}
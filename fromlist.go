@@ -187,6 +187,34 @@ func PathTo(paths []PathEnd, end NI, p []NI) []NI {
 	}
 }
 
+// PathToLabeled decodes a FromList and corresponding arc labels, recovering
+// a single path as a list of labeled edges.
+//
+// Argument labels must be parallel to f.Paths, with labels[n] being the label
+// of the arc from f.Paths[n].From to n, as returned for example by AStarA or
+// populated by Prim.
+//
+// In each edge of the result, N1 is the from node and N2 is the to node that
+// the label belongs to.  The first edge starts at a root node and the last
+// edge ends at the specified end node.
+//
+// PathToLabeled returns nil if end is not in the FromList, that is, if
+// f.Paths[end].Len is 0.  If end is a root node the result is an empty,
+// non-nil slice.
+func (f FromList) PathToLabeled(end NI, labels []LI) []LabeledEdge {
+	n := f.Paths[end].Len
+	if n == 0 {
+		return nil
+	}
+	p := make([]LabeledEdge, n-1)
+	for i := n - 2; i >= 0; i-- {
+		fr := f.Paths[end].From
+		p[i] = LabeledEdge{Edge{fr, end}, labels[end]}
+		end = fr
+	}
+	return p
+}
+
 // Preorder traverses f calling Visitor v in preorder.
 //
 // Nodes are visited in order such that for any node n with from node fr,
@@ -161,6 +161,37 @@ func ExampleFromList_PathTo() {
 	// [3]
 }
 
+func ExampleFromList_PathToLabeled() {
+	//   0
+	//  1| \2
+	//   1  2
+	//  3|  |4
+	//   3--4   5
+	//     5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{0, 2}, 2)
+	g.AddEdge(graph.Edge{1, 3}, 3)
+	g.AddEdge(graph.Edge{2, 4}, 4)
+	g.AddEdge(graph.Edge{3, 4}, 5)
+	g.AddEdge(graph.Edge{5, 5}, 0) // loop, not reachable from 0
+	w := func(l graph.LI) float64 { return float64(l) }
+	h := func(graph.NI) float64 { return 0 }
+	f, labels, dist, _ := g.AStarA(w, 0, 4, h)
+	fmt.Println("dist:", dist)
+	for _, e := range f.PathToLabeled(4, labels) {
+		fmt.Printf("%d->%d label %d\n", e.N1, e.N2, e.LI)
+	}
+	fmt.Println(f.PathToLabeled(0, labels) == nil)
+	fmt.Println(f.PathToLabeled(5, labels) == nil)
+	// Output:
+	// dist: 6
+	// 0->2 label 2
+	// 2->4 label 4
+	// false
+	// true
+}
+
 func ExampleFromList_Preorder() {
 	//     2
 	//    / \
@@ -0,0 +1,102 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package gen
+
+// gen.go has deterministic graph constructors.
+
+import "github.com/soniakeys/graph"
+
+// Complete constructs a complete graph on n nodes.
+//
+// Nodes are numbered 0 through n-1 and every pair of distinct nodes is
+// linked by an edge.
+func Complete(n int) (g graph.Undirected) {
+	g.AdjacencyList = make(graph.AdjacencyList, nonNeg(n))
+	for n1 := 1; n1 < n; n1++ {
+		for n2 := 0; n2 < n1; n2++ {
+			g.AddEdge(graph.NI(n2), graph.NI(n1))
+		}
+	}
+	return
+}
+
+// Cycle constructs a cycle graph on n nodes.
+//
+// Nodes are numbered 0 through n-1 around the cycle.  Each node n1 is linked
+// to node n1+1 and node n-1 is linked back to node 0.  A simple cycle needs
+// at least three nodes; for n less than three the result is Path(n).
+func Cycle(n int) graph.Undirected {
+	g := Path(n)
+	if n >= 3 {
+		g.AddEdge(graph.NI(n-1), 0)
+	}
+	return g
+}
+
+// GridUndirected constructs a rows by cols grid graph.
+//
+// Nodes are numbered in row major order, node r*cols+c being at row r,
+// column c.  Returned function node maps a row and column to the node
+// number.  Each node is linked to the nodes above, below, left, and right
+// of it.  If diagonals is true, each node is also linked to diagonally
+// adjacent nodes, for eight neighbors for nodes not on the edge of the grid.
+func GridUndirected(rows, cols int, diagonals bool) (g graph.Undirected, node func(r, c int) graph.NI) {
+	node = func(r, c int) graph.NI { return graph.NI(r*cols + c) }
+	if rows < 0 || cols < 0 {
+		rows, cols = 0, 0
+	}
+	g.AdjacencyList = make(graph.AdjacencyList, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			n := node(r, c)
+			if c+1 < cols {
+				g.AddEdge(n, node(r, c+1))
+			}
+			if r+1 == rows {
+				continue
+			}
+			g.AddEdge(n, node(r+1, c))
+			if diagonals {
+				if c+1 < cols {
+					g.AddEdge(n, node(r+1, c+1))
+				}
+				if c > 0 {
+					g.AddEdge(n, node(r+1, c-1))
+				}
+			}
+		}
+	}
+	return
+}
+
+// Path constructs a path graph on n nodes.
+//
+// Nodes are numbered 0 through n-1 along the path, node n1 linked to node
+// n1+1.  The ends of the path are nodes 0 and n-1.
+func Path(n int) (g graph.Undirected) {
+	g.AdjacencyList = make(graph.AdjacencyList, nonNeg(n))
+	for n1 := 1; n1 < n; n1++ {
+		g.AddEdge(graph.NI(n1-1), graph.NI(n1))
+	}
+	return
+}
+
+// Star constructs a star graph on n nodes.
+//
+// The center of the star is node 0.  Nodes 1 through n-1 are the leaves,
+// each linked only to node 0.
+func Star(n int) (g graph.Undirected) {
+	g.AdjacencyList = make(graph.AdjacencyList, nonNeg(n))
+	for n1 := 1; n1 < n; n1++ {
+		g.AddEdge(0, graph.NI(n1))
+	}
+	return
+}
+
+func nonNeg(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package gen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soniakeys/graph/gen"
+)
+
+func ExampleComplete() {
+	for fr, to := range gen.Complete(4).AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 2 3]
+	// 1 [0 2 3]
+	// 2 [0 1 3]
+	// 3 [0 1 2]
+}
+
+func ExampleCycle() {
+	for fr, to := range gen.Cycle(4).AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 3]
+	// 1 [0 2]
+	// 2 [1 3]
+	// 3 [2 0]
+}
+
+func ExampleGridUndirected() {
+	// 0--1--2
+	// |  |  |
+	// 3--4--5
+	g, node := gen.GridUndirected(2, 3, false)
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	fmt.Println("row 1, col 2:", node(1, 2))
+	// Output:
+	// 0 [1 3]
+	// 1 [0 2 4]
+	// 2 [1 5]
+	// 3 [0 4]
+	// 4 [1 3 5]
+	// 5 [2 4]
+	// row 1, col 2: 5
+}
+
+func ExampleGridUndirected_diagonals() {
+	g, node := gen.GridUndirected(3, 3, true)
+	fmt.Println("size:", g.Size())
+	fmt.Println("center neighbors:", len(g.AdjacencyList[node(1, 1)]))
+	fmt.Println("corner neighbors:", len(g.AdjacencyList[node(0, 0)]))
+	// Output:
+	// size: 20
+	// center neighbors: 8
+	// corner neighbors: 3
+}
+
+func ExamplePath() {
+	for fr, to := range gen.Path(4).AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [0 2]
+	// 2 [1 3]
+	// 3 [2]
+}
+
+func ExampleStar() {
+	for fr, to := range gen.Star(4).AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 2 3]
+	// 1 [0]
+	// 2 [0]
+	// 3 [0]
+}
+
+func TestGridUndirected(t *testing.T) {
+	for rows := 1; rows < 5; rows++ {
+		for cols := 1; cols < 5; cols++ {
+			want := rows*(cols-1) + cols*(rows-1)
+			g, _ := gen.GridUndirected(rows, cols, false)
+			checkSimple(t, g.AdjacencyList, rows*cols)
+			if g.Size() != want {
+				t.Fatal(rows, cols, "size", g.Size(), "want", want)
+			}
+			want += 2 * (rows - 1) * (cols - 1)
+			g, _ = gen.GridUndirected(rows, cols, true)
+			checkSimple(t, g.AdjacencyList, rows*cols)
+			if g.Size() != want {
+				t.Fatal(rows, cols, "diagonals size", g.Size(), "want", want)
+			}
+		}
+	}
+}
+
+func TestClassic(t *testing.T) {
+	for n := 0; n < 8; n++ {
+		c := gen.Complete(n)
+		checkSimple(t, c.AdjacencyList, n)
+		if want := n * (n - 1) / 2; c.Size() != want {
+			t.Fatal("Complete", n, "size", c.Size(), "want", want)
+		}
+		p := gen.Path(n)
+		checkSimple(t, p.AdjacencyList, n)
+		y := gen.Cycle(n)
+		checkSimple(t, y.AdjacencyList, n)
+		s := gen.Star(n)
+		checkSimple(t, s.AdjacencyList, n)
+		if n == 0 {
+			continue
+		}
+		if p.Size() != n-1 || s.Size() != n-1 {
+			t.Fatal(n, "nodes, Path size", p.Size(), "Star size", s.Size())
+		}
+		want := n
+		if n < 3 {
+			want = n - 1
+		}
+		if y.Size() != want {
+			t.Fatal("Cycle", n, "size", y.Size(), "want", want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+// Gen generates graphs.
+//
+// Random generators take an explicit rand.Source so that results are
+// reproducible.  Generated graphs are simple unless otherwise documented,
+// that is, they have no loops and no parallel arcs or edges.
+package gen
+
+// random.go has random graph generators.
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/soniakeys/graph"
+)
+
+// GnpUndirected generates an Erdős–Rényi G(n, p) random undirected graph.
+//
+// The result has n nodes.  Each of the n(n-1)/2 possible edges is included
+// independently with probability p.
+//
+// An error is returned if n is negative or p is not in the range [0, 1].
+func GnpUndirected(n int, p float64, src rand.Source) (graph.Undirected, error) {
+	if err := checkGnp(n, p); err != nil {
+		return graph.Undirected{}, err
+	}
+	r := rand.New(src)
+	g := graph.Undirected{make(graph.AdjacencyList, n)}
+	for n1 := 1; n1 < n; n1++ {
+		for n2 := 0; n2 < n1; n2++ {
+			if r.Float64() < p {
+				g.AddEdge(graph.NI(n1), graph.NI(n2))
+			}
+		}
+	}
+	return g, nil
+}
+
+// GnpDirected generates an Erdős–Rényi G(n, p) random directed graph.
+//
+// The result has n nodes.  Each of the n(n-1) possible arcs is included
+// independently with probability p.
+//
+// An error is returned if n is negative or p is not in the range [0, 1].
+func GnpDirected(n int, p float64, src rand.Source) (graph.Directed, error) {
+	if err := checkGnp(n, p); err != nil {
+		return graph.Directed{}, err
+	}
+	r := rand.New(src)
+	a := make(graph.AdjacencyList, n)
+	for fr := range a {
+		for to := 0; to < n; to++ {
+			if to != fr && r.Float64() < p {
+				a[fr] = append(a[fr], graph.NI(to))
+			}
+		}
+	}
+	return graph.Directed{a}, nil
+}
+
+func checkGnp(n int, p float64) error {
+	if n < 0 {
+		return errors.New("negative number of nodes")
+	}
+	if !(p >= 0 && p <= 1) {
+		return errors.New("probability p not in range [0, 1]")
+	}
+	return nil
+}
+
+// GnmUndirected generates an Erdős–Rényi G(n, m) random undirected graph.
+//
+// The result has n nodes and m edges, chosen uniformly from all such simple
+// graphs.
+//
+// An error is returned if n or m is negative or if m is greater than the
+// n(n-1)/2 edges possible in a simple graph.
+func GnmUndirected(n, m int, src rand.Source) (graph.Undirected, error) {
+	if n < 0 {
+		return graph.Undirected{}, errors.New("negative number of nodes")
+	}
+	ks, err := sample(int64(n)*int64(n-1)/2, m, src)
+	if err != nil {
+		return graph.Undirected{}, err
+	}
+	// edges are numbered by rows of the lower triangle of the adjacency
+	// matrix.  row n1 holds edges to nodes 0 through n1-1.
+	g := graph.Undirected{make(graph.AdjacencyList, n)}
+	n1, row := int64(1), int64(0) // row is the number of the first edge of n1
+	for _, k := range ks {
+		for k >= row+n1 {
+			row += n1
+			n1++
+		}
+		g.AddEdge(graph.NI(n1), graph.NI(k-row))
+	}
+	return g, nil
+}
+
+// GnmDirected generates an Erdős–Rényi G(n, m) random directed graph.
+//
+// The result has n nodes and m arcs, chosen uniformly from all such simple
+// directed graphs.
+//
+// An error is returned if n or m is negative or if m is greater than the
+// n(n-1) arcs possible in a simple directed graph.
+func GnmDirected(n, m int, src rand.Source) (graph.Directed, error) {
+	if n < 0 {
+		return graph.Directed{}, errors.New("negative number of nodes")
+	}
+	ks, err := sample(int64(n)*int64(n-1), m, src)
+	if err != nil {
+		return graph.Directed{}, err
+	}
+	// arcs are numbered by rows of the adjacency matrix, omitting the
+	// diagonal.
+	a := make(graph.AdjacencyList, n)
+	for _, k := range ks {
+		fr := k / int64(n-1)
+		to := k % int64(n-1)
+		if to >= fr {
+			to++
+		}
+		a[fr] = append(a[fr], graph.NI(to))
+	}
+	return graph.Directed{a}, nil
+}
+
+// BarabasiAlbert generates a random scale-free undirected graph by the
+// Barabási–Albert preferential attachment model.
+//
+// The graph starts as a complete graph on nodes 0 through m, a seed clique
+// of m+1 nodes.  Each further node up to n-1 is then added with edges to m
+// distinct existing nodes, chosen with probability proportional to their
+// degree.  The result is simple, connected, and has n nodes and
+// m(m+1)/2 + (n-m-1)m edges.
+//
+// An error is returned if m is less than 1 or if n is less than m+1.
+func BarabasiAlbert(n, m int, src rand.Source) (graph.Undirected, error) {
+	if m < 1 {
+		return graph.Undirected{}, errors.New("m must be at least 1")
+	}
+	if n < m+1 {
+		return graph.Undirected{}, errors.New("n less than m+1")
+	}
+	r := rand.New(src)
+	g := graph.Undirected{make(graph.AdjacencyList, n)}
+	// ends lists the end nodes of all edges.  a node appears once for each
+	// incident edge so a uniform choice from ends is proportional to degree.
+	var ends []graph.NI
+	for n1 := 1; n1 <= m; n1++ {
+		for n2 := 0; n2 < n1; n2++ {
+			g.AddEdge(graph.NI(n1), graph.NI(n2))
+			ends = append(ends, graph.NI(n1), graph.NI(n2))
+		}
+	}
+	var chosen graph.Bits
+	targets := make([]graph.NI, m)
+	for nd := m + 1; nd < n; nd++ {
+		chosen.Clear()
+		for i := range targets {
+			t := ends[r.Intn(len(ends))]
+			for chosen.Bit(t) == 1 {
+				t = ends[r.Intn(len(ends))]
+			}
+			chosen.SetBit(t, 1)
+			targets[i] = t
+		}
+		for _, t := range targets {
+			g.AddEdge(graph.NI(nd), t)
+			ends = append(ends, graph.NI(nd), t)
+		}
+	}
+	return g, nil
+}
+
+// WattsStrogatz generates a random small-world undirected graph by the
+// Watts–Strogatz model.
+//
+// The graph starts as a ring lattice of n nodes where each node is linked
+// to its k nearest neighbors, k/2 on each side.  Then going around the ring
+// k/2 times, first for edges to nearest neighbors, then for edges to next
+// nearest, and so on, each edge n1-n2 is rewired with probability beta to
+// n1-w, where w is chosen uniformly from the nodes that would not create
+// a loop or parallel edge.  Rewiring is skipped for a node already linked
+// to all other nodes.  The result is simple and has n nodes and nk/2 edges.
+//
+// An error is returned if k is negative or odd, if k is not less than n,
+// or if beta is not in the range [0, 1].
+func WattsStrogatz(n, k int, beta float64, src rand.Source) (graph.Undirected, error) {
+	switch {
+	case k < 0 || k%2 == 1:
+		return graph.Undirected{}, errors.New("k must be a non-negative even number")
+	case k >= n:
+		return graph.Undirected{}, errors.New("k must be less than n")
+	case !(beta >= 0 && beta <= 1):
+		return graph.Undirected{}, errors.New("probability beta not in range [0, 1]")
+	}
+	r := rand.New(src)
+	adj := make([]graph.Bits, n) // adjacency matrix
+	deg := make([]int, n)
+	edges := make([]graph.Edge, 0, n*k/2)
+	for j := 1; j <= k/2; j++ {
+		for n1 := 0; n1 < n; n1++ {
+			n2 := (n1 + j) % n
+			edges = append(edges, graph.Edge{graph.NI(n1), graph.NI(n2)})
+			adj[n1].SetBit(graph.NI(n2), 1)
+			adj[n2].SetBit(graph.NI(n1), 1)
+			deg[n1]++
+			deg[n2]++
+		}
+	}
+	for x, e := range edges {
+		if deg[e.N1] == n-1 || r.Float64() >= beta {
+			continue
+		}
+		// retry until w would create neither a loop nor a parallel edge
+		w := graph.NI(r.Intn(n))
+		for w == e.N1 || adj[e.N1].Bit(w) == 1 {
+			w = graph.NI(r.Intn(n))
+		}
+		adj[e.N1].SetBit(e.N2, 0)
+		adj[e.N2].SetBit(e.N1, 0)
+		adj[e.N1].SetBit(w, 1)
+		adj[w].SetBit(e.N1, 1)
+		deg[e.N2]--
+		deg[w]++
+		edges[x].N2 = w
+	}
+	g := graph.Undirected{make(graph.AdjacencyList, n)}
+	for _, e := range edges {
+		g.AddEdge(e.N1, e.N2)
+	}
+	return g, nil
+}
+
+// sample returns m distinct numbers chosen uniformly from [0, max), sorted.
+//
+// The algorithm is Robert Floyd's, taking time proportional to m.
+func sample(max int64, m int, src rand.Source) ([]int64, error) {
+	if m < 0 {
+		return nil, errors.New("negative number of edges")
+	}
+	if int64(m) > max {
+		return nil, errors.New("too many edges for a simple graph")
+	}
+	r := rand.New(src)
+	chosen := make(map[int64]bool, m)
+	ks := make(int64s, 0, m)
+	for j := max - int64(m); j < max; j++ {
+		k := r.Int63n(j + 1)
+		if chosen[k] {
+			k = j
+		}
+		chosen[k] = true
+		ks = append(ks, k)
+	}
+	sort.Sort(ks)
+	return ks, nil
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
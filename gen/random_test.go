@@ -0,0 +1,210 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package gen_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/gen"
+)
+
+func ExampleGnmUndirected() {
+	g, err := gen.GnmUndirected(10, 20, rand.NewSource(1))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("order:", len(g.AdjacencyList))
+	fmt.Println("size:", g.Size())
+	_, err = gen.GnmUndirected(10, 46, rand.NewSource(1))
+	fmt.Println(err)
+	// Output:
+	// order: 10
+	// size: 20
+	// too many edges for a simple graph
+}
+
+func ExampleGnpDirected() {
+	g, err := gen.GnpDirected(5, 1, rand.NewSource(1))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	_, err = gen.GnpDirected(5, 1.5, rand.NewSource(1))
+	fmt.Println(err)
+	// Output:
+	// 0 [1 2 3 4]
+	// 1 [0 2 3 4]
+	// 2 [0 1 3 4]
+	// 3 [0 1 2 4]
+	// 4 [0 1 2 3]
+	// probability p not in range [0, 1]
+}
+
+// checkSimple fails t if a is not simple or has the wrong order.
+func checkSimple(t *testing.T, a graph.AdjacencyList, n int) {
+	if len(a) != n {
+		t.Fatal("order", len(a), "want", n)
+	}
+	if s, x := a.IsSimple(); !s {
+		t.Fatal("not simple at node", x)
+	}
+}
+
+func TestGnp(t *testing.T) {
+	src := rand.NewSource(3)
+	for i := 0; i < 20; i++ {
+		n := rand.New(src).Intn(30)
+		p := rand.New(src).Float64()
+		u, err := gen.GnpUndirected(n, p, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, u.AdjacencyList, n)
+		if ok, _, _ := u.IsUndirected(); !ok {
+			t.Fatal("not undirected")
+		}
+		d, err := gen.GnpDirected(n, p, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, d.AdjacencyList, n)
+	}
+	if _, err := gen.GnpUndirected(-1, .5, src); err == nil {
+		t.Fatal("negative n accepted")
+	}
+	if _, err := gen.GnpUndirected(5, -.5, src); err == nil {
+		t.Fatal("negative p accepted")
+	}
+}
+
+func TestGnm(t *testing.T) {
+	src := rand.NewSource(4)
+	for i := 0; i < 50; i++ {
+		r := rand.New(src)
+		n := r.Intn(30)
+		mu := 0
+		if n > 1 {
+			mu = r.Intn(n*(n-1)/2 + 1)
+		}
+		u, err := gen.GnmUndirected(n, mu, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, u.AdjacencyList, n)
+		if ok, _, _ := u.IsUndirected(); !ok {
+			t.Fatal("not undirected")
+		}
+		if s := u.Size(); s != mu {
+			t.Fatal("size", s, "want", mu)
+		}
+		md := 0
+		if n > 1 {
+			md = r.Intn(n*(n-1) + 1)
+		}
+		d, err := gen.GnmDirected(n, md, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, d.AdjacencyList, n)
+		if s := d.ArcSize(); s != md {
+			t.Fatal("arc size", s, "want", md)
+		}
+	}
+	if _, err := gen.GnmDirected(3, 7, src); err == nil {
+		t.Fatal("too many arcs accepted")
+	}
+	if _, err := gen.GnmUndirected(3, -1, src); err == nil {
+		t.Fatal("negative m accepted")
+	}
+}
+
+func ExampleBarabasiAlbert() {
+	g, err := gen.BarabasiAlbert(100, 2, rand.NewSource(1))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("order:", len(g.AdjacencyList))
+	fmt.Println("size:", g.Size())
+	fmt.Println("connected:", g.IsConnected())
+	// Output:
+	// order: 100
+	// size: 197
+	// connected: true
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	src := rand.NewSource(5)
+	for i := 0; i < 20; i++ {
+		r := rand.New(src)
+		m := 1 + r.Intn(5)
+		n := m + 1 + r.Intn(50)
+		g, err := gen.BarabasiAlbert(n, m, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, g.AdjacencyList, n)
+		if want := m*(m+1)/2 + (n-m-1)*m; g.Size() != want {
+			t.Fatal("size", g.Size(), "want", want)
+		}
+		if !g.IsConnected() {
+			t.Fatal("not connected")
+		}
+	}
+	if _, err := gen.BarabasiAlbert(3, 3, src); err == nil {
+		t.Fatal("n < m+1 accepted")
+	}
+}
+
+func ExampleWattsStrogatz() {
+	// with beta 0, the result is the ring lattice
+	g, err := gen.WattsStrogatz(6, 4, 0, rand.NewSource(1))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1 5 2 4]
+	// 1 [0 2 3 5]
+	// 2 [1 3 0 4]
+	// 3 [2 4 1 5]
+	// 4 [3 5 2 0]
+	// 5 [4 0 3 1]
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	src := rand.NewSource(6)
+	for i := 0; i < 30; i++ {
+		r := rand.New(src)
+		k := 2 * r.Intn(5)
+		n := k + 1 + r.Intn(30)
+		g, err := gen.WattsStrogatz(n, k, r.Float64(), src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, g.AdjacencyList, n)
+		if ok, _, _ := g.IsUndirected(); !ok {
+			t.Fatal("not undirected")
+		}
+		if want := n * k / 2; g.Size() != want {
+			t.Fatal("size", g.Size(), "want", want)
+		}
+	}
+	if _, err := gen.WattsStrogatz(10, 3, .5, src); err == nil {
+		t.Fatal("odd k accepted")
+	}
+	if _, err := gen.WattsStrogatz(4, 4, .5, src); err == nil {
+		t.Fatal("k >= n accepted")
+	}
+}
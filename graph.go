@@ -136,6 +136,15 @@ type LabeledUndirected struct {
 // Edge is an undirected edge between nodes N1 and N2.
 type Edge struct{ N1, N2 NI }
 
+// EdgeList satisfies sort.Interface, ordering edges by N1, then N2.
+type EdgeList []Edge
+
+func (l EdgeList) Len() int { return len(l) }
+func (l EdgeList) Less(i, j int) bool {
+	return l[i].N1 < l[j].N1 || l[i].N1 == l[j].N1 && l[i].N2 < l[j].N2
+}
+func (l EdgeList) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+
 // LabeledEdge is an undirected edge with an associated label.
 type LabeledEdge struct {
 	Edge
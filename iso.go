@@ -0,0 +1,404 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// iso.go has subgraph isomorphism by the VF2 algorithm and a canonical form
+// for isomorphism testing.
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SubgraphIsomorphisms finds all mappings of pattern into g.
+//
+// A mapping is an injective function from the nodes of pattern to the nodes
+// of g such that for every arc p->q of pattern, g has an arc from the image
+// of p to the image of q.  That is, pattern is matched to a subgraph of g,
+// not necessarily an induced subgraph.  Arcs of g between images of nodes
+// not adjacent in pattern are allowed.  A loop in pattern must map to
+// a loop in g.  Parallel arcs are treated as single arcs.
+//
+// Each match is returned as a slice of length len(pattern), mapping pattern
+// node i to the host node match[i].  Graphs with symmetry have multiple
+// matches for the same subgraph of g.  A pattern with no nodes has a single
+// empty match.
+//
+// The search is the VF2 algorithm of Cordella, Foggia, Sansone, and Vento,
+// with feasibility rules adapted to non-induced matching.  Time can be
+// exponential but is practical for small patterns.  See HasSubgraph to
+// test for any match without finding all of them.
+func (g AdjacencyList) SubgraphIsomorphisms(pattern AdjacencyList) (matches [][]NI) {
+	newVF2(pattern, g).match(1, func(m []NI) bool {
+		matches = append(matches, append([]NI{}, m...))
+		return true
+	})
+	return
+}
+
+// HasSubgraph returns true if pattern can be mapped into g.
+//
+// Mappings are as described for SubgraphIsomorphisms.  HasSubgraph returns
+// as soon as a single mapping is found.
+func (g AdjacencyList) HasSubgraph(pattern AdjacencyList) bool {
+	found := false
+	newVF2(pattern, g).match(1, func([]NI) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// vf2Graph holds one of the two graphs of a VF2 search.
+type vf2Graph struct {
+	succ, pred [][]NI // distinct neighbors, excluding loops
+	loop       Bits
+	core       []NI  // mapped node of the other graph, or -1
+	out, in    []int // depth at which nodes entered terminal sets, or 0
+}
+
+func newVF2Graph(a AdjacencyList) *vf2Graph {
+	v := &vf2Graph{
+		succ: make([][]NI, len(a)),
+		pred: make([][]NI, len(a)),
+		core: make([]NI, len(a)),
+		out:  make([]int, len(a)),
+		in:   make([]int, len(a)),
+	}
+	var seen Bits
+	for fr, to := range a {
+		seen.Clear()
+		for _, to := range to {
+			switch {
+			case to == NI(fr):
+				v.loop.SetBit(to, 1)
+			case seen.Bit(to) == 0:
+				seen.SetBit(to, 1)
+				v.succ[fr] = append(v.succ[fr], to)
+				v.pred[to] = append(v.pred[to], NI(fr))
+			}
+		}
+		v.core[fr] = -1
+	}
+	return v
+}
+
+// add records the mapping of n at the given depth, updating terminal sets.
+func (v *vf2Graph) add(n, m NI, depth int) {
+	v.core[n] = m
+	if v.out[n] == 0 {
+		v.out[n] = depth
+	}
+	if v.in[n] == 0 {
+		v.in[n] = depth
+	}
+	for _, s := range v.succ[n] {
+		if v.out[s] == 0 {
+			v.out[s] = depth
+		}
+	}
+	for _, p := range v.pred[n] {
+		if v.in[p] == 0 {
+			v.in[p] = depth
+		}
+	}
+}
+
+// remove undoes add.
+func (v *vf2Graph) remove(n NI, depth int) {
+	v.core[n] = -1
+	if v.out[n] == depth {
+		v.out[n] = 0
+	}
+	if v.in[n] == depth {
+		v.in[n] = 0
+	}
+	for _, s := range v.succ[n] {
+		if v.out[s] == depth {
+			v.out[s] = 0
+		}
+	}
+	for _, p := range v.pred[n] {
+		if v.in[p] == depth {
+			v.in[p] = 0
+		}
+	}
+}
+
+// counts returns numbers of unmapped neighbors of n in terminal sets and
+// in total, for the VF2 lookahead rules.
+func (v *vf2Graph) counts(n NI) (c [6]int) {
+	for _, s := range v.succ[n] {
+		if v.core[s] < 0 {
+			c[0]++
+			if v.out[s] > 0 {
+				c[1]++
+			}
+			if v.in[s] > 0 {
+				c[2]++
+			}
+		}
+	}
+	for _, p := range v.pred[n] {
+		if v.core[p] < 0 {
+			c[3]++
+			if v.out[p] > 0 {
+				c[4]++
+			}
+			if v.in[p] > 0 {
+				c[5]++
+			}
+		}
+	}
+	return
+}
+
+type vf2 struct {
+	p, h  *vf2Graph // pattern and host
+	hArcs []Bits    // host arcs as adjacency matrix
+}
+
+func newVF2(pattern, host AdjacencyList) *vf2 {
+	s := &vf2{
+		p:     newVF2Graph(pattern),
+		h:     newVF2Graph(host),
+		hArcs: make([]Bits, len(host)),
+	}
+	for fr, to := range s.h.succ {
+		for _, to := range to {
+			s.hArcs[fr].SetBit(to, 1)
+		}
+	}
+	return s
+}
+
+// match extends the current partial mapping, calling emit for each complete
+// mapping.  It returns false if emit returns false.
+func (s *vf2) match(depth int, emit func([]NI) bool) bool {
+	p, h := s.p, s.h
+	if depth > len(p.core) {
+		return emit(p.core)
+	}
+	// choose the next pattern node, preferring the out terminal set,
+	// then the in terminal set.  host candidates must be in the
+	// corresponding host set.
+	pn := NI(-1)
+	var inSet func(NI) bool
+	for n, m := range p.core {
+		if m < 0 && p.out[n] > 0 {
+			pn = NI(n)
+			inSet = func(c NI) bool { return h.out[c] > 0 }
+			break
+		}
+	}
+	if pn < 0 {
+		for n, m := range p.core {
+			if m < 0 && p.in[n] > 0 {
+				pn = NI(n)
+				inSet = func(c NI) bool { return h.in[c] > 0 }
+				break
+			}
+		}
+	}
+	if pn < 0 {
+		for n, m := range p.core {
+			if m < 0 {
+				pn = NI(n)
+				inSet = func(NI) bool { return true }
+				break
+			}
+		}
+	}
+	pc := p.counts(pn)
+	for hn, m := range h.core {
+		if m >= 0 || !inSet(NI(hn)) || !s.feasible(pn, NI(hn), pc) {
+			continue
+		}
+		p.add(pn, NI(hn), depth)
+		h.add(NI(hn), pn, depth)
+		ok := s.match(depth+1, emit)
+		p.remove(pn, depth)
+		h.remove(NI(hn), depth)
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// feasible tests whether pattern node pn can map to host node hn.
+// pc is p.counts(pn).
+func (s *vf2) feasible(pn, hn NI, pc [6]int) bool {
+	p, h := s.p, s.h
+	if p.loop.Bit(pn) == 1 && h.loop.Bit(hn) == 0 {
+		return false
+	}
+	// arcs to and from mapped nodes must be present in the host
+	for _, q := range p.succ[pn] {
+		if m := p.core[q]; m >= 0 && s.hArcs[hn].Bit(m) == 0 {
+			return false
+		}
+	}
+	for _, q := range p.pred[pn] {
+		if m := p.core[q]; m >= 0 && s.hArcs[m].Bit(hn) == 0 {
+			return false
+		}
+	}
+	// lookahead.  unmapped neighbors of pn must map to distinct unmapped
+	// neighbors of hn in corresponding terminal sets.
+	hc := h.counts(hn)
+	for i, c := range pc {
+		if c > hc[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalForm returns a string that is the same for isomorphic graphs.
+//
+// Two graphs g and h are isomorphic if and only if g.CanonicalForm() ==
+// h.CanonicalForm().  The guarantee holds for graphs without parallel
+// edges, as parallel edges are treated as single edges.  Loops are
+// represented.
+//
+// The form encodes the adjacency matrix of g under a node ordering chosen
+// by color refinement followed by backtracking search over the remaining
+// choices, keeping the lexicographically least matrix.  The search does no
+// pruning by automorphisms and so time is exponential for highly symmetric
+// graphs, regular graphs for example, where refinement does not distinguish
+// nodes.  It is practical for graphs of modest size, up to a few tens of
+// nodes.
+func (g Undirected) CanonicalForm() string {
+	a := g.AdjacencyList
+	adj := make([]Bits, len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			adj[fr].SetBit(to, 1)
+		}
+	}
+	if len(a) == 0 {
+		return "0:"
+	}
+	all := make([]NI, len(a))
+	for n := range all {
+		all[n] = NI(n)
+	}
+	var best string
+	var search func(cells [][]NI)
+	search = func(cells [][]NI) {
+		cells = refine(cells, adj)
+		for i, c := range cells {
+			if len(c) == 1 {
+				continue
+			}
+			// individualize each node of the first non-singleton cell
+			for _, v := range c {
+				rest := make([]NI, 0, len(c)-1)
+				for _, n := range c {
+					if n != v {
+						rest = append(rest, n)
+					}
+				}
+				next := append([][]NI{}, cells[:i]...)
+				next = append(next, []NI{v}, rest)
+				search(append(next, cells[i+1:]...))
+			}
+			return
+		}
+		// all cells are singletons, giving a node ordering
+		if f := matrixForm(cells, adj); best == "" || f < best {
+			best = f
+		}
+	}
+	search([][]NI{all})
+	return best
+}
+
+// refine refines an ordered partition of nodes until it is equitable.
+//
+// Each cell is split by the number of neighbors of its nodes in each cell.
+// The ordering of the result depends only on the structure of the graph
+// and the ordering of the argument partition, not on node numbers.
+func refine(cells [][]NI, adj []Bits) [][]NI {
+	for {
+		cellOf := map[NI]int{}
+		for x, c := range cells {
+			for _, n := range c {
+				cellOf[n] = x
+			}
+		}
+		var next [][]NI
+		for _, c := range cells {
+			if len(c) == 1 {
+				next = append(next, c)
+				continue
+			}
+			sig := map[NI][]int{}
+			for _, n := range c {
+				s := make([]int, len(cells))
+				adj[n].Iterate(func(nb NI) bool {
+					s[cellOf[nb]]++
+					return true
+				})
+				sig[n] = s
+			}
+			c = append([]NI{}, c...)
+			sort.Sort(sigSorter{c, sig})
+			start := 0
+			for x := 1; x <= len(c); x++ {
+				if x == len(c) || sigLess(sig[c[start]], sig[c[x]]) {
+					next = append(next, c[start:x])
+					start = x
+				}
+			}
+		}
+		if len(next) == len(cells) {
+			return next
+		}
+		cells = next
+	}
+}
+
+// sigSorter sorts nodes by signature.
+type sigSorter struct {
+	nodes []NI
+	sig   map[NI][]int
+}
+
+func (s sigSorter) Len() int { return len(s.nodes) }
+func (s sigSorter) Less(i, j int) bool {
+	return sigLess(s.sig[s.nodes[i]], s.sig[s.nodes[j]])
+}
+func (s sigSorter) Swap(i, j int) { s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i] }
+
+func sigLess(s, t []int) bool {
+	for x := range s {
+		if s[x] != t[x] {
+			return s[x] < t[x]
+		}
+	}
+	return false
+}
+
+// matrixForm encodes the upper triangle of the adjacency matrix, with the
+// diagonal, under the node ordering of a partition of singleton cells.
+func matrixForm(cells [][]NI, adj []Bits) string {
+	n := len(cells)
+	var b []byte
+	var d, nd byte // hex digit accumulator and number of bits in it
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			d = d<<1 | byte(adj[cells[i][0]].Bit(cells[j][0]))
+			if nd++; nd == 4 {
+				b = append(b, "0123456789abcdef"[d])
+				d, nd = 0, 0
+			}
+		}
+	}
+	if nd > 0 {
+		b = append(b, "0123456789abcdef"[d<<(4-nd)])
+	}
+	return fmt.Sprintf("%d:%s", n, b)
+}
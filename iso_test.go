@@ -0,0 +1,218 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_SubgraphIsomorphisms() {
+	// host graph, arcs directed right or down:
+	//
+	//   0-->1-->2
+	//   |   |
+	//   v   v
+	//   3-->4
+	g := graph.AdjacencyList{
+		0: {1, 3},
+		1: {2, 4},
+		3: {4},
+		4: {},
+	}
+	// pattern: a path of two arcs
+	p := graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {},
+	}
+	for _, m := range g.SubgraphIsomorphisms(p) {
+		fmt.Println(m)
+	}
+	// Output:
+	// [0 1 2]
+	// [0 1 4]
+	// [0 3 4]
+}
+
+func ExampleAdjacencyList_HasSubgraph() {
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {0},
+	}
+	cycle3 := graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {0},
+	}
+	loop := graph.AdjacencyList{
+		0: {0},
+	}
+	fmt.Println(g.HasSubgraph(cycle3))
+	fmt.Println(g.HasSubgraph(loop))
+	// Output:
+	// true
+	// false
+}
+
+// bruteSubgraph enumerates all injective mappings of p into g.
+func bruteSubgraph(g, p graph.AdjacencyList) (matches []string) {
+	m := make([]graph.NI, len(p))
+	used := make([]bool, len(g))
+	var f func(i int)
+	f = func(i int) {
+		if i == len(p) {
+			for fr, to := range p {
+				for _, to := range to {
+					if ok, _ := g.HasArc(m[fr], m[to]); !ok {
+						return
+					}
+				}
+			}
+			matches = append(matches, fmt.Sprint(m))
+			return
+		}
+		for n := range g {
+			if !used[n] {
+				used[n] = true
+				m[i] = graph.NI(n)
+				f(i + 1)
+				used[n] = false
+			}
+		}
+	}
+	f(0)
+	return
+}
+
+func TestSubgraphIsomorphisms(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	randGraph := func(n, m int) graph.AdjacencyList {
+		a := make(graph.AdjacencyList, n)
+		for ; m > 0; m-- {
+			fr := r.Intn(n)
+			a[fr] = append(a[fr], graph.NI(r.Intn(n)))
+		}
+		return a
+	}
+	for i := 0; i < 200; i++ {
+		gn := 1 + r.Intn(7)
+		g := randGraph(gn, r.Intn(3*gn))
+		pn := 1 + r.Intn(4)
+		p := randGraph(pn, r.Intn(2*pn))
+		var got []string
+		for _, m := range g.SubgraphIsomorphisms(p) {
+			got = append(got, fmt.Sprint(m))
+		}
+		want := bruteSubgraph(g, p)
+		sort.Strings(got)
+		sort.Strings(want)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatal("g", g, "p", p, "got", got, "want", want)
+		}
+		if g.HasSubgraph(p) != (len(want) > 0) {
+			t.Fatal("HasSubgraph", !(len(want) > 0))
+		}
+	}
+}
+
+func ExampleUndirected_CanonicalForm() {
+	// two labelings of a path of three nodes
+	var g, h graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	h.AddEdge(1, 0)
+	h.AddEdge(0, 2)
+	// and a triangle
+	var k graph.Undirected
+	k.AddEdge(0, 1)
+	k.AddEdge(1, 2)
+	k.AddEdge(2, 0)
+	fmt.Println(g.CanonicalForm() == h.CanonicalForm())
+	fmt.Println(g.CanonicalForm() == k.CanonicalForm())
+	// Output:
+	// true
+	// false
+}
+
+// bruteIsomorphic tests all permutations.
+func bruteIsomorphic(g, h graph.Undirected) bool {
+	a, b := g.AdjacencyList, h.AdjacencyList
+	if len(a) != len(b) {
+		return false
+	}
+	m := make([]graph.NI, len(a))
+	used := make([]bool, len(a))
+	var f func(i int) bool
+	f = func(i int) bool {
+		if i == len(a) {
+			for fr := range a {
+				for to := range a {
+					x, _ := a.HasArc(graph.NI(fr), graph.NI(to))
+					y, _ := b.HasArc(m[fr], m[to])
+					if x != y {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		for n := range b {
+			if !used[n] {
+				used[n] = true
+				m[i] = graph.NI(n)
+				if f(i + 1) {
+					return true
+				}
+				used[n] = false
+			}
+		}
+		return false
+	}
+	return f(0)
+}
+
+func TestCanonicalForm(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	randGraph := func(n int) graph.Undirected {
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		p := r.Float64()
+		for n1 := 0; n1 < n; n1++ {
+			for n2 := n1; n2 < n; n2++ {
+				if r.Float64() < p {
+					g.AddEdge(graph.NI(n1), graph.NI(n2))
+				}
+			}
+		}
+		return g
+	}
+	for i := 0; i < 300; i++ {
+		n := r.Intn(8)
+		g := randGraph(n)
+		// relabeled copy must have the same form
+		perm := r.Perm(n)
+		h := graph.Undirected{make(graph.AdjacencyList, n)}
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if graph.NI(fr) <= to {
+					h.AddEdge(graph.NI(perm[fr]), graph.NI(perm[to]))
+				}
+			}
+		}
+		if g.CanonicalForm() != h.CanonicalForm() {
+			t.Fatal("relabeled", g, h)
+		}
+		// random graph of the same order
+		k := randGraph(n)
+		if (g.CanonicalForm() == k.CanonicalForm()) != bruteIsomorphic(g, k) {
+			t.Fatal("forms", g.CanonicalForm(), k.CanonicalForm(),
+				"isomorphic", bruteIsomorphic(g, k))
+		}
+	}
+}
@@ -5,6 +5,7 @@ package graph
 
 import (
 	"container/heap"
+	"fmt"
 	"sort"
 )
 
@@ -13,21 +14,47 @@ type dsElement struct {
 	rank int
 }
 
-type disjointSet struct {
+// DisjointSet is a union-find data structure over the elements 0 through
+// n-1, typically graph nodes.
+//
+// It supports incremental connectivity queries.  Initially each element is
+// in a set by itself.  Union merges sets and Find returns a representative
+// element of a set.  The implementation uses union by rank and path
+// compression.
+//
+// DisjointSet holds a slice and has value receivers.  A copy of
+// a DisjointSet shares state with the original.
+type DisjointSet struct {
 	set []dsElement
 }
 
-func newDisjointSet(n int) disjointSet {
+// NewDisjointSet constructs a DisjointSet for elements 0 through n-1,
+// each initially in a set by itself.
+func NewDisjointSet(n int) DisjointSet {
 	set := make([]dsElement, n)
 	for i := range set {
 		set[i].from = -1
 	}
-	return disjointSet{set}
+	return DisjointSet{set}
+}
+
+// Union merges the sets containing elements a and b.
+//
+// It returns true if disjoint sets were merged, false if a and b were
+// already in the same set.
+func (ds DisjointSet) Union(a, b int) bool {
+	return ds.union(NI(a), NI(b))
+}
+
+// Find returns the representative element of the set containing x.
+//
+// Elements a and b are in the same set exactly when Find(a) == Find(b).
+// Representatives may change as sets are merged by Union.
+func (ds DisjointSet) Find(x int) int {
+	return int(ds.find(NI(x)))
 }
 
-// return true if disjoint trees were combined.
-// false if x and y were already in the same tree.
-func (ds disjointSet) union(x, y NI) bool {
+func (ds DisjointSet) union(x, y NI) bool {
 	xr := ds.find(x)
 	yr := ds.find(y)
 	if xr == yr {
@@ -45,7 +72,7 @@ func (ds disjointSet) union(x, y NI) bool {
 	return true
 }
 
-func (ds disjointSet) find(n NI) NI {
+func (ds DisjointSet) find(n NI) NI {
 	// fast paths for n == root or from root.
 	// no updates need in these cases.
 	s := ds.set
@@ -79,6 +106,57 @@ func (ds disjointSet) find(n NI) NI {
 	}
 }
 
+// LabeledAdjacencyList constructs a directed labeled adjacency list from
+// the receiver edge list.
+//
+// Each edge N1, N2 of the edge list becomes an arc from N1 to N2 with the
+// edge label.  The result has length l.Order.
+//
+// An error is returned if any edge has an end node outside the range
+// [0, l.Order).
+func (l WeightedEdgeList) LabeledAdjacencyList() (LabeledAdjacencyList, error) {
+	if err := l.checkEnds(); err != nil {
+		return nil, err
+	}
+	g := make(LabeledAdjacencyList, l.Order)
+	for _, e := range l.Edges {
+		g[e.N1] = append(g[e.N1], Half{e.N2, e.LI})
+	}
+	return g, nil
+}
+
+// LabeledUndirected constructs an undirected labeled graph from the receiver
+// edge list.
+//
+// Each edge N1, N2 of the edge list becomes a pair of reciprocal arcs with
+// the edge label, or a single arc in the case of a loop.  The result has
+// l.Order nodes.
+//
+// An error is returned if any edge has an end node outside the range
+// [0, l.Order).
+func (l WeightedEdgeList) LabeledUndirected() (LabeledUndirected, error) {
+	if err := l.checkEnds(); err != nil {
+		return LabeledUndirected{}, err
+	}
+	g := LabeledUndirected{make(LabeledAdjacencyList, l.Order)}
+	for _, e := range l.Edges {
+		g.AddEdge(e.Edge, e.LI)
+	}
+	return g, nil
+}
+
+// checkEnds returns an error if any edge end is not a node of l.
+func (l WeightedEdgeList) checkEnds() error {
+	for _, e := range l.Edges {
+		if e.N1 < 0 || int(e.N1) >= l.Order ||
+			e.N2 < 0 || int(e.N2) >= l.Order {
+			return fmt.Errorf("edge %d-%d end node out of range for order %d",
+				e.N1, e.N2, l.Order)
+		}
+	}
+	return nil
+}
+
 // Kruskal implements Kruskal's algorithm for constructing a minimum spanning
 // forest on an undirected graph.
 //
@@ -94,12 +172,77 @@ func (ds disjointSet) find(n NI) NI {
 //
 // The edge list of the receiver is sorted as a side effect of this method.
 // See KruskalSorted for a version that relies on the edge list being already
-// sorted.
+// sorted, KruskalStable for a deterministic result, and KruskalWith for
+// control over how ties in weight are broken.
 func (l WeightedEdgeList) Kruskal() (g LabeledUndirected, dist float64) {
 	sort.Sort(l)
 	return l.KruskalSorted()
 }
 
+// KruskalMax implements Kruskal's algorithm for constructing a maximum
+// spanning forest on an undirected graph.
+//
+// The edge list is sorted in descending order of weight, then the forest
+// is constructed by KruskalSorted, the same core used by Kruskal.  The
+// result maximizes total distance rather than minimizing it.
+//
+// The edge list of the receiver is sorted as a side effect of this method.
+// Otherwise the method is like Kruskal.
+func (l WeightedEdgeList) KruskalMax() (g LabeledUndirected, dist float64) {
+	sort.Sort(sort.Reverse(l))
+	return l.KruskalSorted()
+}
+
+// KruskalWith implements Kruskal's algorithm using a caller supplied ordering
+// of edges.
+//
+// Argument less must define a strict ordering of edges.  For the result to
+// be a minimum spanning forest it must order edges by weight, but it can
+// additionally break ties, for example by node numbers or labels.  When less
+// fully orders the edge list, the result is deterministic regardless of the
+// initial order of edges.
+//
+// The edge list of the receiver is sorted as a side effect of this method.
+// Otherwise the method is like Kruskal.
+func (l WeightedEdgeList) KruskalWith(less func(a, b LabeledEdge) bool) (g LabeledUndirected, dist float64) {
+	sort.Sort(edgeSorter{l.Edges, less})
+	return l.KruskalSorted()
+}
+
+// KruskalStable implements Kruskal's algorithm with a deterministic order
+// of edges.
+//
+// Edges are ordered by weight, ties broken by N1, then N2, then label.
+// The result then does not depend on the initial order of the edge list.
+//
+// The edge list of the receiver is sorted as a side effect of this method.
+// Otherwise the method is like Kruskal.  See KruskalWith for other orderings.
+func (l WeightedEdgeList) KruskalStable() (g LabeledUndirected, dist float64) {
+	return l.KruskalWith(func(a, b LabeledEdge) bool {
+		wa, wb := l.WeightFunc(a.LI), l.WeightFunc(b.LI)
+		switch {
+		case wa != wb:
+			return wa < wb
+		case a.N1 != b.N1:
+			return a.N1 < b.N1
+		case a.N2 != b.N2:
+			return a.N2 < b.N2
+		}
+		return a.LI < b.LI
+	})
+}
+
+// edgeSorter implements sort.Interface for a labeled edge list with
+// an arbitrary less function.
+type edgeSorter struct {
+	edges []LabeledEdge
+	less  func(a, b LabeledEdge) bool
+}
+
+func (s edgeSorter) Len() int           { return len(s.edges) }
+func (s edgeSorter) Less(i, j int) bool { return s.less(s.edges[i], s.edges[j]) }
+func (s edgeSorter) Swap(i, j int)      { s.edges[i], s.edges[j] = s.edges[j], s.edges[i] }
+
 // KruskalSorted implements Kruskal's algorithm for constructing a minimum
 // spanning tree on an undirected graph.
 //
@@ -110,13 +253,15 @@ func (l WeightedEdgeList) Kruskal() (g LabeledUndirected, dist float64) {
 // parallel arcs) are allowed though, and do not affect the result.
 //
 // When called, the edge list of the receiver must be already sorted by weight.
-// See Kruskal for a version that accepts an unsorted edge list.
+// See Kruskal for a version that accepts an unsorted edge list.  If the edge
+// list is sorted by descending weight, the result is a maximum spanning
+// forest.  See KruskalMax.
 //
 // The forest is returned as an undirected graph.
 //
 // Also returned is a total distance for the returned forest.
 func (l WeightedEdgeList) KruskalSorted() (g LabeledUndirected, dist float64) {
-	ds := newDisjointSet(l.Order)
+	ds := NewDisjointSet(l.Order)
 	g.LabeledAdjacencyList = make(LabeledAdjacencyList, l.Order)
 	for _, e := range l.Edges {
 		if ds.union(e.N1, e.N2) {
@@ -127,6 +272,68 @@ func (l WeightedEdgeList) KruskalSorted() (g LabeledUndirected, dist float64) {
 	return
 }
 
+// Boruvka implements Borůvka's algorithm for constructing a minimum spanning
+// forest on an undirected graph.
+//
+// The algorithm proceeds in rounds.  In each round the minimum weight edge
+// leaving each tree of the forest is found and all such edges are added to
+// the forest, at least halving the number of trees.  Ties in weight are
+// broken by position in the edge list.
+//
+// As with Kruskal, the receiver edge list need not contain reciprocal arcs,
+// and reciprocal and parallel arcs are allowed and do not affect the result.
+// Loops are ignored.  Unlike Kruskal, the edge list is not modified.
+//
+// If the graph is not connected, the result is a minimum spanning forest
+// and dist is the total distance summed over all trees of the forest.
+func (l WeightedEdgeList) Boruvka() (g LabeledUndirected, dist float64) {
+	ds := NewDisjointSet(l.Order)
+	g.LabeledAdjacencyList = make(LabeledAdjacencyList, l.Order)
+	w := make([]float64, len(l.Edges))
+	for x, e := range l.Edges {
+		w[x] = l.WeightFunc(e.LI)
+	}
+	// lighter returns true if edge x is lighter than edge y.
+	lighter := func(x, y int) bool {
+		return w[x] < w[y] || w[x] == w[y] && x < y
+	}
+	cheapest := make([]int, l.Order) // edge index, by tree root
+	for {
+		for i := range cheapest {
+			cheapest[i] = -1
+		}
+		for x, e := range l.Edges {
+			r1 := ds.find(e.N1)
+			r2 := ds.find(e.N2)
+			if r1 == r2 {
+				continue
+			}
+			if c := cheapest[r1]; c < 0 || lighter(x, c) {
+				cheapest[r1] = x
+			}
+			if c := cheapest[r2]; c < 0 || lighter(x, c) {
+				cheapest[r2] = x
+			}
+		}
+		merged := false
+		for _, x := range cheapest {
+			if x < 0 {
+				continue
+			}
+			// an edge may be cheapest for both of its trees.  union adds
+			// it only once.
+			if e := l.Edges[x]; ds.union(e.N1, e.N2) {
+				g.AddEdge(e.Edge, e.LI)
+				dist += w[x]
+				merged = true
+			}
+		}
+		if !merged {
+			return
+		}
+	}
+}
+
 // Prim implements the Jarník-Prim-Dijkstra algorithm for constructing
 // a minimum spanning tree on an undirected graph.
 //
@@ -205,6 +412,105 @@ func (g LabeledUndirected) Prim(start NI, w WeightFunc, f *FromList, labels []LI
 	return
 }
 
+// PrimDense implements the Jarník-Prim-Dijkstra algorithm with an unordered
+// frontier rather than a priority queue.
+//
+// Each step scans the frontier linearly for the minimum weight arc, giving
+// O(n²) time for a graph of n nodes but avoiding the cost of maintaining
+// a heap.  This is the classic choice for dense graphs, where the number of
+// edges approaches n².  Which of Prim and PrimDense is faster depends on the
+// graph and the weights though.  If performance matters, benchmark both on
+// representative data.
+//
+// Arguments and results are as for Prim, and the total distance will be the
+// same as that computed by Prim.  If the graph has multiple minimum spanning
+// trees, the tree found may be different.
+func (g LabeledUndirected) PrimDense(start NI, w WeightFunc, f *FromList, labels []LI, componentLeaves *Bits) (numSpanned int, dist float64) {
+	al := g.LabeledAdjacencyList
+	if len(f.Paths) != len(al) {
+		*f = NewFromList(len(al))
+	}
+	rp := f.Paths
+	best := make([]float64, len(al)) // weight of best arc to frontier node
+	from := make([]fromHalf, len(al))
+	var frontier []NI
+	inFrontier := make([]bool, len(al))
+	rp[start] = PathEnd{From: -1, Len: 1}
+	numSpanned = 1
+	fLeaves := &f.Leaves
+	fLeaves.SetBit(start, 1)
+	if componentLeaves != nil {
+		componentLeaves.SetBit(start, 1)
+	}
+	for a := start; ; {
+		for _, nb := range al[a] {
+			n := nb.To
+			if rp[n].Len > 0 {
+				continue // already in MST, no action
+			}
+			switch wt := w(nb.Label); {
+			case !inFrontier[n]: // new node for frontier
+				inFrontier[n] = true
+				frontier = append(frontier, n)
+				best[n] = wt
+				from[n] = fromHalf{From: a, Label: nb.Label}
+			case wt < best[n]: // better arc
+				best[n] = wt
+				from[n] = fromHalf{From: a, Label: nb.Label}
+			}
+		}
+		if len(frontier) == 0 {
+			break // done
+		}
+		// linear search for the best frontier node
+		x := 0
+		for i, n := range frontier {
+			if best[n] < best[frontier[x]] {
+				x = i
+			}
+		}
+		a = frontier[x]
+		last := len(frontier) - 1
+		frontier[x] = frontier[last]
+		frontier = frontier[:last]
+		fr := from[a].From
+		rp[a].Len = rp[fr].Len + 1
+		rp[a].From = fr
+		if len(labels) != 0 {
+			labels[a] = from[a].Label
+		}
+		dist += best[a]
+		fLeaves.SetBit(fr, 0)
+		fLeaves.SetBit(a, 1)
+		if componentLeaves != nil {
+			componentLeaves.SetBit(fr, 0)
+			componentLeaves.SetBit(a, 1)
+		}
+		numSpanned++
+	}
+	return
+}
+
+// PrimEdges constructs a minimum spanning tree on the connected component
+// containing start, returning the tree as a list of edges.
+//
+// It is a convenience wrapper on Prim for when the tree is wanted simply as
+// a list of edges.  Each edge has N1 as the node nearer to start and N2 as
+// the node farther from start.  Also returned is the total spanned distance,
+// the same as returned by Prim.
+func (g LabeledUndirected) PrimEdges(start NI, w WeightFunc) (tree []LabeledEdge, dist float64) {
+	var f FromList
+	labels := make([]LI, len(g.LabeledAdjacencyList))
+	ns, dist := g.Prim(start, w, &f, labels, nil)
+	tree = make([]LabeledEdge, 0, ns-1)
+	for n, e := range f.Paths {
+		if e.Len > 1 {
+			tree = append(tree, LabeledEdge{Edge{e.From, NI(n)}, labels[n]})
+		}
+	}
+	return
+}
+
 // fromHalf is a half arc, representing a labeled arc and the "neighbor" node
 // that the arc originates from.
 //
@@ -5,11 +5,104 @@ package graph_test
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/soniakeys/graph"
 )
 
+func ExampleDisjointSet() {
+	ds := graph.NewDisjointSet(5)
+	fmt.Println(ds.Union(0, 1))
+	fmt.Println(ds.Union(3, 4))
+	fmt.Println(ds.Union(1, 0))
+	fmt.Println(ds.Find(0) == ds.Find(1))
+	fmt.Println(ds.Find(1) == ds.Find(3))
+	// Output:
+	// true
+	// true
+	// false
+	// true
+	// false
+}
+
+func TestDisjointSet(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(30)
+		ds := graph.NewDisjointSet(n)
+		// naive set labels for comparison
+		set := make([]int, n)
+		for j := range set {
+			set[j] = j
+		}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			x, y := r.Intn(n), r.Intn(n)
+			sx, sy := set[x], set[y]
+			if got := ds.Union(x, y); got != (sx != sy) {
+				t.Fatal("Union", x, y, "returned", got)
+			}
+			for k, s := range set {
+				if s == sy {
+					set[k] = sx
+				}
+			}
+			for k := range set {
+				for m := range set {
+					same := ds.Find(k) == ds.Find(m)
+					if same != (set[k] == set[m]) {
+						t.Fatal("Find", k, m, "same set", same)
+					}
+				}
+			}
+		}
+	}
+}
+
+func ExampleWeightedEdgeList_LabeledAdjacencyList() {
+	l := graph.WeightedEdgeList{3, nil, []graph.LabeledEdge{
+		{graph.Edge{0, 1}, 10},
+		{graph.Edge{1, 2}, 20},
+		{graph.Edge{2, 2}, 30},
+	}}
+	g, err := l.LabeledAdjacencyList()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range g {
+		fmt.Println(n, to)
+	}
+	l.Edges = append(l.Edges, graph.LabeledEdge{graph.Edge{2, 3}, 40})
+	_, err = l.LabeledAdjacencyList()
+	fmt.Println(err)
+	// Output:
+	// 0 [{1 10}]
+	// 1 [{2 20}]
+	// 2 [{2 30}]
+	// edge 2-3 end node out of range for order 3
+}
+
+func ExampleWeightedEdgeList_LabeledUndirected() {
+	l := graph.WeightedEdgeList{3, nil, []graph.LabeledEdge{
+		{graph.Edge{0, 1}, 10},
+		{graph.Edge{1, 2}, 20},
+		{graph.Edge{2, 2}, 30},
+	}}
+	g, err := l.LabeledUndirected()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range g.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 0 [{1 10}]
+	// 1 [{0 10} {2 20}]
+	// 2 [{1 20} {2 30}]
+}
+
 func ExampleWeightedEdgeList_Kruskal() {
 	//       (10)
 	//     0------4----\
@@ -88,6 +181,272 @@ func ExampleWeightedEdgeList_KruskalSorted() {
 	// total distance:  110
 }
 
+func ExampleWeightedEdgeList_KruskalWith() {
+	// all edges have equal weight
+	//
+	//     0------1
+	//     |\     |
+	//     | \    |
+	//     |  \   |
+	//     |   \  |
+	//     3------2
+	w := func(l graph.LI) float64 { return 1 }
+	l := graph.WeightedEdgeList{4, w, []graph.LabeledEdge{
+		{graph.Edge{2, 3}, 0},
+		{graph.Edge{0, 2}, 1},
+		{graph.Edge{1, 2}, 2},
+		{graph.Edge{0, 3}, 3},
+		{graph.Edge{0, 1}, 4},
+	}}
+	// order by weight, then by node numbers
+	t, dist := l.KruskalWith(func(a, b graph.LabeledEdge) bool {
+		wa, wb := w(a.LI), w(b.LI)
+		switch {
+		case wa != wb:
+			return wa < wb
+		case a.N1 != b.N1:
+			return a.N1 < b.N1
+		}
+		return a.N2 < b.N2
+	})
+	fmt.Println("spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning tree as undirected graph:
+	// 0 [{1 4} {2 1} {3 3}]
+	// 1 [{0 4}]
+	// 2 [{0 1}]
+	// 3 [{0 3}]
+	// total distance:  3
+}
+
+func ExampleWeightedEdgeList_KruskalStable() {
+	// all edges have equal weight
+	//
+	//     0------1
+	//     |\     |
+	//     | \    |
+	//     |  \   |
+	//     |   \  |
+	//     3------2
+	w := func(l graph.LI) float64 { return 1 }
+	l := graph.WeightedEdgeList{4, w, []graph.LabeledEdge{
+		{graph.Edge{2, 3}, 0},
+		{graph.Edge{0, 2}, 1},
+		{graph.Edge{1, 2}, 2},
+		{graph.Edge{0, 3}, 3},
+		{graph.Edge{0, 1}, 4},
+	}}
+	t, dist := l.KruskalStable()
+	fmt.Println("spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning tree as undirected graph:
+	// 0 [{1 4} {2 1} {3 3}]
+	// 1 [{0 4}]
+	// 2 [{0 1}]
+	// 3 [{0 3}]
+	// total distance:  3
+}
+
+func ExampleWeightedEdgeList_KruskalMax() {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3
+	//       (50)   (20)
+	w := func(l graph.LI) float64 { return float64(l) }
+	l := graph.WeightedEdgeList{5, w, []graph.LabeledEdge{
+		{graph.Edge{0, 1}, 30},
+		{graph.Edge{0, 4}, 10},
+		{graph.Edge{1, 2}, 50},
+		{graph.Edge{1, 4}, 40},
+		{graph.Edge{2, 3}, 20},
+		{graph.Edge{2, 4}, 60},
+		{graph.Edge{3, 4}, 70},
+	}}
+	t, dist := l.KruskalMax()
+	fmt.Println("maximum spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// maximum spanning tree as undirected graph:
+	// 0 [{1 30}]
+	// 1 [{2 50} {0 30}]
+	// 2 [{4 60} {1 50}]
+	// 3 [{4 70}]
+	// 4 [{3 70} {2 60}]
+	// total distance:  210
+}
+
+func TestKruskalMax(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(20)
+		var e []graph.LabeledEdge
+		for j := r.Intn(3 * n); j > 0; j-- {
+			e = append(e, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(10))})
+		}
+		_, max := graph.WeightedEdgeList{n,
+			func(l graph.LI) float64 { return float64(l) },
+			append([]graph.LabeledEdge{}, e...)}.KruskalMax()
+		// a minimum spanning forest with negated weights is a maximum
+		// spanning forest.
+		_, min := graph.WeightedEdgeList{n,
+			func(l graph.LI) float64 { return -float64(l) }, e}.Kruskal()
+		if max != -min {
+			t.Fatal("KruskalMax", max, "want", -min)
+		}
+	}
+}
+
+func TestKruskalWith(t *testing.T) {
+	// the tree must not depend on the initial order of equal-weight edges
+	w := func(l graph.LI) float64 { return float64(l % 2) }
+	edges := []graph.LabeledEdge{
+		{graph.Edge{0, 1}, 0},
+		{graph.Edge{1, 2}, 2},
+		{graph.Edge{2, 3}, 4},
+		{graph.Edge{3, 0}, 6},
+		{graph.Edge{0, 2}, 1},
+		{graph.Edge{1, 3}, 3},
+	}
+	less := func(a, b graph.LabeledEdge) bool {
+		wa, wb := w(a.LI), w(b.LI)
+		switch {
+		case wa != wb:
+			return wa < wb
+		case a.N1 != b.N1:
+			return a.N1 < b.N1
+		}
+		return a.N2 < b.N2
+	}
+	var want string
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		e := make([]graph.LabeledEdge, len(edges))
+		for j, k := range r.Perm(len(edges)) {
+			e[j] = edges[k]
+		}
+		tr, dist := graph.WeightedEdgeList{4, w, e}.KruskalWith(less)
+		if dist != 0 {
+			t.Fatal("dist", dist)
+		}
+		got := fmt.Sprint(tr.LabeledAdjacencyList)
+		if i == 0 {
+			want = got
+		} else if got != want {
+			t.Fatal("got", got, "want", want)
+		}
+	}
+}
+
+func TestKruskalStable(t *testing.T) {
+	// the tree must not depend on the initial order of edges
+	r := rand.New(rand.NewSource(10))
+	for i := 0; i < 20; i++ {
+		n := 1 + r.Intn(10)
+		var edges []graph.LabeledEdge
+		for j := r.Intn(3 * n); j > 0; j-- {
+			edges = append(edges, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(6))})
+		}
+		w := func(l graph.LI) float64 { return float64(l / 3) }
+		var want string
+		for k := 0; k < 10; k++ {
+			e := make([]graph.LabeledEdge, len(edges))
+			for j, x := range r.Perm(len(edges)) {
+				e[j] = edges[x]
+			}
+			tr, _ := graph.WeightedEdgeList{n, w, e}.KruskalStable()
+			got := fmt.Sprint(tr.LabeledAdjacencyList)
+			if k == 0 {
+				want = got
+			} else if got != want {
+				t.Fatal("got", got, "want", want)
+			}
+		}
+	}
+}
+
+func ExampleWeightedEdgeList_Boruvka() {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3    5------6
+	//       (50)   (20)        (80)
+	w := func(l graph.LI) float64 { return float64(l) }
+	l := graph.WeightedEdgeList{7, w, []graph.LabeledEdge{
+		{graph.Edge{0, 1}, 30},
+		{graph.Edge{0, 4}, 10},
+		{graph.Edge{1, 2}, 50},
+		{graph.Edge{1, 4}, 40},
+		{graph.Edge{2, 3}, 20},
+		{graph.Edge{2, 4}, 60},
+		{graph.Edge{3, 4}, 70},
+		{graph.Edge{5, 6}, 80},
+	}}
+	t, dist := l.Boruvka()
+	fmt.Println("spanning forest as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning forest as undirected graph:
+	// 0 [{4 10} {1 30}]
+	// 1 [{0 30} {2 50}]
+	// 2 [{3 20} {1 50}]
+	// 3 [{2 20}]
+	// 4 [{0 10}]
+	// 5 [{6 80}]
+	// 6 [{5 80}]
+	// total distance:  190
+}
+
+func TestBoruvka(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(20)
+		var e []graph.LabeledEdge
+		for j := r.Intn(3 * n); j > 0; j-- {
+			e = append(e, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(10))}) // small range gives ties
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		bt, bd := graph.WeightedEdgeList{n, w, e}.Boruvka()
+		// copy, as Kruskal sorts the list
+		k := graph.WeightedEdgeList{n, w, append([]graph.LabeledEdge{}, e...)}
+		kt, kd := k.Kruskal()
+		if bd != kd {
+			t.Fatal("Boruvka dist", bd, "Kruskal", kd)
+		}
+		if bs, ks := bt.Size(), kt.Size(); bs != ks {
+			t.Fatal("Boruvka size", bs, "Kruskal", ks)
+		}
+		// a forest has one fewer edge than nodes for each tree
+		if reps, _ := bt.ConnectedComponentReps(); bt.Size() != n-len(reps) {
+			t.Fatal("Boruvka result not a forest")
+		}
+	}
+}
+
 func ExampleLabeledUndirected_Prim() {
 	// graph:
 	//
@@ -169,6 +528,24 @@ func ExampleLabeledUndirected_Prim() {
 	// 4:  []graph.Half{graph.Half{To:3, Label:2}}
 }
 
+func ExampleLabeledUndirected_PrimEdges() {
+	//  (2)
+	//   |\
+	//   | \
+	// 4 |  \ 5
+	//   |   \
+	//  (1)--(0)
+	//     3
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 4)
+	g.AddEdge(graph.Edge{2, 0}, 5)
+	w := func(arcLabel graph.LI) float64 { return float64(arcLabel) }
+	fmt.Println(g.PrimEdges(0, w))
+	// Output:
+	// [{{0 1} 3} {{1 2} 4}] 7
+}
+
 var u100 = r100.l.Undirected()
 
 func TestPrim100(t *testing.T) {
@@ -193,3 +570,72 @@ func BenchmarkPrim100(b *testing.B) {
 		}
 	}
 }
+
+func TestPrimEdges(t *testing.T) {
+	reps, _ := u100.ConnectedComponentReps()
+	w := func(l graph.LI) float64 { return r100.w[l] }
+	for _, r := range reps {
+		var f graph.FromList
+		labels := make([]graph.LI, len(u100.LabeledAdjacencyList))
+		ns, want := u100.Prim(r, w, &f, labels, nil)
+		tree, dist := u100.PrimEdges(r, w)
+		if dist != want {
+			t.Fatal("dist", dist, "want", want)
+		}
+		if len(tree) != ns-1 {
+			t.Fatal(len(tree), "edges for", ns, "nodes")
+		}
+		for _, e := range tree {
+			if f.Paths[e.N2].From != e.N1 || labels[e.N2] != e.LI {
+				t.Fatal("edge", e, "not in FromList result")
+			}
+		}
+	}
+}
+
+func TestPrimDense(t *testing.T) {
+	reps, _ := u100.ConnectedComponentReps()
+	w := func(l graph.LI) float64 { return r100.w[l] }
+	var f, fd graph.FromList
+	for _, r := range reps {
+		ns, dist := u100.Prim(r, w, &f, nil, nil)
+		nsd, distd := u100.PrimDense(r, w, &fd, nil, nil)
+		if nsd != ns || distd != dist {
+			t.Fatal("PrimDense", nsd, distd, "Prim", ns, dist)
+		}
+	}
+}
+
+// denseGraph returns a complete graph with random weights.
+func denseGraph() (g graph.LabeledUndirected, w graph.WeightFunc) {
+	r := rand.New(rand.NewSource(1))
+	const n = 300
+	var wt []float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			g.AddEdge(graph.Edge{graph.NI(i), graph.NI(j)}, graph.LI(len(wt)))
+			wt = append(wt, r.Float64())
+		}
+	}
+	return g, func(l graph.LI) float64 { return wt[l] }
+}
+
+func BenchmarkLabeledUndirected_Prim_dense(b *testing.B) {
+	g, w := denseGraph()
+	var f graph.FromList
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Paths = nil
+		g.Prim(0, w, &f, nil, nil)
+	}
+}
+
+func BenchmarkLabeledUndirected_PrimDense(b *testing.B) {
+	g, w := denseGraph()
+	var f graph.FromList
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Paths = nil
+		g.PrimDense(0, w, &f, nil, nil)
+	}
+}
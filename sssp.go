@@ -352,6 +352,11 @@ func (p *openHeap) Pop() interface{} {
 // cycle not reachable from start will not prevent the algorithm from finding
 // shortest paths from start.
 //
+// The algorithm makes at most len(g)-1 relaxation passes over all arcs,
+// stopping early if a pass makes no improvement, followed by one pass to
+// detect negative cycles.  Time complexity is O(VE).  Nodes not reachable
+// from start are left with a distance of math.Inf(1).
+//
 // See also NegativeCycle to find a cycle anywhere in the graph, and see
 // HasNegativeCycle for lighter-weight negative cycle detection,
 func (g LabeledDirected) BellmanFord(w WeightFunc, start NI) (f FromList, dist []float64, end NI) {
@@ -781,15 +786,36 @@ func (g LabeledDirected) DAGOptimalPaths(start, end NI, ordering []NI, w WeightF
 // As usual for Dijkstra's algorithm, arc weights must be non-negative.
 // Graphs may be directed or undirected.  Loops and parallel arcs are
 // allowed.
+//
+// If end is a valid node, the search stops when a shortest path to end is
+// found.  Use end = -1 to find shortest paths to all nodes reachable from
+// start.  Returned reached is the number of nodes found in this case, -1
+// otherwise.
+//
+// Paths are returned in FromList f.  Nodes not reached have PathEnd.Len 0
+// and From -1.  Argument dist contains shortest path distances for nodes
+// where the search determined a shortest path and math.Inf(1) for all others.
 func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList, dist []float64, reached int) {
+	return g.dijkstra(start, end, w, nil)
+}
+
+// dijkstra implements Dijkstra.  If labels is non-nil it is populated with
+// labels of the arcs of the shortest path tree.
+func (g LabeledAdjacencyList) dijkstra(start, end NI, w WeightFunc, labels []LI) (f FromList, dist []float64, reached int) {
 	r := make([]tentResult, len(g))
 	for i := range r {
 		r[i].nx = NI(i)
 	}
 	f = NewFromList(len(g))
 	dist = make([]float64, len(g))
-	current := start
 	rp := f.Paths
+	inf := math.Inf(1)
+	for i := range dist {
+		dist[i] = inf
+		rp[i].From = -1
+	}
+	current := start
+	dist[current] = 0
 	rp[current] = PathEnd{Len: 1, From: -1} // path length at start is 1 node
 	cr := &r[current]
 	cr.dist = 0    // distance at start is 0.
@@ -822,6 +848,9 @@ func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList,
 			hr.dist = dist
 			rp[nb.To].Len = nextLen
 			rp[nb.To].From = current
+			if labels != nil {
+				labels[nb.To] = nb.Label
+			}
 			if visited {
 				heap.Fix(&t, hr.fx)
 			} else {
@@ -843,6 +872,20 @@ func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList,
 	return f, dist, -1
 }
 
+// DijkstraLabels finds shortest paths from start to all reachable nodes,
+// returning also the arc labels of the shortest path tree.
+//
+// Results f and dist are as returned by Dijkstra with end = -1.  Result
+// labels has the length of g.  For each node n reached other than start,
+// labels[n] is the label of the arc from f.Paths[n].From to n.  Together,
+// f and labels identify the exact labeled arcs of each path even where the
+// graph has parallel arcs.  See FromList.PathToLabeled.
+func (g LabeledAdjacencyList) DijkstraLabels(start NI, w WeightFunc) (f FromList, dist []float64, labels []LI) {
+	labels = make([]LI, len(g))
+	f, dist, _ = g.dijkstra(start, -1, w, labels)
+	return
+}
+
 // DijkstraPath finds a single shortest path.
 //
 // Returned is the path and distance as returned by FromList.PathTo.
@@ -851,6 +894,271 @@ func (g LabeledAdjacencyList) DijkstraPath(start, end NI, w WeightFunc) ([]NI, f
 	return f.PathTo(end, nil), dist[end]
 }
 
+// Johnson finds all pairs shortest distances using Johnson's algorithm.
+//
+// Arc weights may be negative.  If the graph contains a negative cycle,
+// Johnson returns a nil matrix and negCycle = true.  Otherwise in result
+// dist, dist[i][j] is the shortest distance from node i to node j, or
+// math.Inf(1) if j is not reachable from i.
+//
+// The method runs a Bellman-Ford pass from a virtual source connected to
+// all nodes, then uses the computed potentials to reweight arcs to be
+// non-negative and runs Dijkstra from each node.  For sparse graphs this
+// is faster than FloydWarshall.
+func (g LabeledAdjacencyList) Johnson(w WeightFunc) (dist [][]float64, negCycle bool) {
+	// potentials, by Bellman-Ford from a virtual source with zero weight
+	// arcs to all nodes.
+	h := make([]float64, len(g))
+	relax := func() (imp bool) {
+		for fr, to := range g {
+			hf := h[fr]
+			for _, to := range to {
+				if d := hf + w(to.Label); d < h[to.To] {
+					h[to.To] = d
+					imp = true
+				}
+			}
+		}
+		return
+	}
+	for _ = range g {
+		if !relax() {
+			break
+		}
+	}
+	if relax() {
+		return nil, true
+	}
+	// reweighted copy of g where labels index a table of new weights
+	rw := make(LabeledAdjacencyList, len(g))
+	var wt []float64
+	for fr, to := range g {
+		r := make([]Half, len(to))
+		for x, nb := range to {
+			d := w(nb.Label) + h[fr] - h[nb.To]
+			if d < 0 {
+				d = 0 // floating point error, mathematically d >= 0
+			}
+			nb.Label = LI(len(wt))
+			wt = append(wt, d)
+			r[x] = nb
+		}
+		rw[fr] = r
+	}
+	rww := func(l LI) float64 { return wt[l] }
+	dist = make([][]float64, len(g))
+	for fr := range g {
+		_, d, _ := rw.Dijkstra(NI(fr), -1, rww)
+		for to, dt := range d {
+			d[to] = dt - h[fr] + h[to]
+		}
+		dist[fr] = d
+	}
+	return dist, false
+}
+
+// KShortestPaths finds up to k shortest loopless paths from start to end
+// using Yen's algorithm.
+//
+// Paths are returned as node sequences from start to end, in order of
+// increasing distance, with dists holding the corresponding distances.
+// Fewer than k paths are returned if fewer exist.  If end is not reachable
+// from start, both results are nil.
+//
+// Paths are distinguished by node sequence.  Where parallel arcs exist
+// between consecutive nodes of a path, the distance uses the arc of least
+// weight.  As with Dijkstra, arc weights must be non-negative.
+func (g LabeledDirected) KShortestPaths(start, end NI, k int, w WeightFunc) (paths [][]NI, dists []float64) {
+	if k < 1 {
+		return
+	}
+	a := g.LabeledAdjacencyList
+	// copy of a where labels index a table of weights and arc ends
+	c := make(LabeledAdjacencyList, len(a))
+	var wt []float64
+	var arcTo []NI
+	for fr, to := range a {
+		r := make([]Half, len(to))
+		for x, nb := range to {
+			r[x] = Half{nb.To, LI(len(wt))}
+			wt = append(wt, w(nb.Label))
+			arcTo = append(arcTo, nb.To)
+		}
+		c[fr] = r
+	}
+	// arcs and nodes removed for a spur path search are given infinite
+	// weight.
+	cutArc := make([]bool, len(wt))
+	cutNode := make([]bool, len(a))
+	inf := math.Inf(1)
+	cw := func(l LI) float64 {
+		if cutArc[l] || cutNode[arcTo[l]] {
+			return inf
+		}
+		return wt[l]
+	}
+	shortest := func(s NI) ([]NI, float64, bool) {
+		f, d, _ := c.Dijkstra(s, end, cw)
+		if d[end] == inf {
+			return nil, 0, false
+		}
+		return f.PathTo(end, nil), d[end], true
+	}
+	// arcDist is the least weight of arcs fr->to.
+	arcDist := func(fr, to NI) float64 {
+		d := inf
+		for _, nb := range c[fr] {
+			if nb.To == to && wt[nb.Label] < d {
+				d = wt[nb.Label]
+			}
+		}
+		return d
+	}
+	p, d, ok := shortest(start)
+	if !ok {
+		return
+	}
+	paths = [][]NI{p}
+	dists = []float64{d}
+	seen := map[string]bool{fmt.Sprint(p): true}
+	var cand [][]NI // candidate paths
+	var candDist []float64
+	for len(paths) < k {
+		prev := paths[len(paths)-1]
+		rootDist := 0.
+		for i, spur := range prev[:len(prev)-1] {
+			for x := range cutArc {
+				cutArc[x] = false
+			}
+			for x := range cutNode {
+				cutNode[x] = false
+			}
+			// cut arcs from spur taken by found paths sharing the root
+		found:
+			for _, p := range paths {
+				if len(p) <= i+1 {
+					continue
+				}
+				for j, n := range prev[:i+1] {
+					if p[j] != n {
+						continue found
+					}
+				}
+				for _, nb := range c[spur] {
+					if nb.To == p[i+1] {
+						cutArc[nb.Label] = true
+					}
+				}
+			}
+			// cut root nodes other than spur
+			for _, n := range prev[:i] {
+				cutNode[n] = true
+			}
+			if sp, sd, ok := shortest(spur); ok {
+				p := append(append([]NI{}, prev[:i]...), sp...)
+				if key := fmt.Sprint(p); !seen[key] {
+					seen[key] = true
+					cand = append(cand, p)
+					candDist = append(candDist, rootDist+sd)
+				}
+			}
+			rootDist += arcDist(spur, prev[i+1])
+		}
+		if len(cand) == 0 {
+			break
+		}
+		// move best candidate to result
+		b := 0
+		for x, d := range candDist {
+			if d < candDist[b] {
+				b = x
+			}
+		}
+		paths = append(paths, cand[b])
+		dists = append(dists, candDist[b])
+		last := len(cand) - 1
+		cand[b], candDist[b] = cand[last], candDist[last]
+		cand, candDist = cand[:last], candDist[:last]
+	}
+	return
+}
+
+// ReachableWithin finds nodes reachable from start within a distance budget.
+//
+// The result is a bitmap of nodes n where the shortest path distance from
+// start to n is less than or equal to budget.  Distance is the sum of arc
+// weights returned by w.  Start is included as long as budget is not negative.
+//
+// ReachableWithin runs Dijkstra's algorithm but does not extend paths that
+// exceed the budget.  As with Dijkstra, arc weights must be non-negative.
+// For unweighted reachability by number of arcs, from all nodes at once,
+// see AdjacencyList.ReachableWithinHops.
+func (g LabeledDirected) ReachableWithin(start NI, budget float64, w WeightFunc) (b Bits) {
+	if budget < 0 {
+		return
+	}
+	a := g.LabeledAdjacencyList
+	r := make([]tentResult, len(a))
+	for i := range r {
+		r[i].nx = NI(i)
+	}
+	cr := &r[start]
+	cr.done = true
+	b.SetBit(start, 1)
+	var t tent
+	for {
+		for _, nb := range a[cr.nx] {
+			hr := &r[nb.To]
+			if hr.done {
+				continue
+			}
+			d := cr.dist + w(nb.Label)
+			switch {
+			case d > budget: // prune
+			case b.Bit(nb.To) == 0: // new node within budget
+				b.SetBit(nb.To, 1)
+				hr.dist = d
+				heap.Push(&t, hr)
+			case d < hr.dist: // better distance
+				hr.dist = d
+				heap.Fix(&t, hr.fx)
+			}
+		}
+		if len(t) == 0 {
+			return
+		}
+		cr = heap.Pop(&t).(*tentResult)
+		cr.done = true
+	}
+}
+
+// ShortestPathDAG returns the subgraph of arcs on shortest paths from start.
+//
+// The result has the node set of g and contains each arc fr->to of g where
+// fr is reachable from start and dist[fr] + w(arc) == dist[to], with dist
+// being shortest path distances computed by Dijkstra.  Every path from start
+// in the result is a shortest path in g and every shortest path from start
+// in g is in the result.
+//
+// Arc weights must be non-negative.  If they are positive the result is
+// acyclic.  Zero weight arcs can form cycles in the result.
+func (g LabeledDirected) ShortestPathDAG(start NI, w WeightFunc) Directed {
+	a := g.LabeledAdjacencyList
+	f, dist, _ := a.Dijkstra(start, -1, w)
+	d := make(AdjacencyList, len(a))
+	for fr, to := range a {
+		if f.Paths[fr].Len == 0 {
+			continue
+		}
+		for _, to := range to {
+			if dist[NI(fr)]+w(to.Label) == dist[to.To] {
+				d[fr] = append(d[fr], to.To)
+			}
+		}
+	}
+	return Directed{d}
+}
+
 // tent implements container/heap
 func (t tent) Len() int           { return len(t) }
 func (t tent) Less(i, j int) bool { return t[i].dist < t[j].dist }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/soniakeys/graph"
@@ -43,6 +44,53 @@ func ExampleLabeledAdjacencyList_AStarAPath() {
 	// Path distance: 26
 }
 
+func ExampleLabeledAdjacencyList_AStarAPath_grid() {
+	// A 4x3 grid of cells with a wall at cells 5 and 6.  Adjacent open cells
+	// are connected in both directions with unit cost.  Manhattan distance
+	// is an admissible heuristic.
+	//
+	//   0  1  2  3
+	//   4  #  #  7
+	//   8  9 10 11
+	const cols = 4
+	open := ".....##....."
+	var g graph.LabeledAdjacencyList = make([][]graph.Half, len(open))
+	link := func(a, b int) {
+		if open[a] == '.' && open[b] == '.' {
+			g[a] = append(g[a], graph.Half{To: graph.NI(b)})
+			g[b] = append(g[b], graph.Half{To: graph.NI(a)})
+		}
+	}
+	for c := range open {
+		if c%cols < cols-1 {
+			link(c, c+1)
+		}
+		if c+cols < len(open) {
+			link(c, c+cols)
+		}
+	}
+	w := func(graph.LI) float64 { return 1 }
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	h := func(end graph.NI) graph.Heuristic {
+		return func(n graph.NI) float64 {
+			return float64(abs(int(n)%cols-int(end)%cols) +
+				abs(int(n)/cols-int(end)/cols))
+		}
+	}
+	p, d := g.AStarAPath(1, 10, h(10), w)
+	fmt.Println("Path:", p, "distance:", d)
+	p, _ = g.AStarAPath(1, 5, h(5), w)
+	fmt.Println("Path to wall:", p)
+	// Output:
+	// Path: [1 2 3 7 11 10] distance: 5
+	// Path to wall: []
+}
+
 func ExampleLabeledAdjacencyList_AStarMPath() {
 	// arcs are directed right:
 	//       -----------------------
@@ -435,6 +483,23 @@ func ExampleLabeledAdjacencyList_DijkstraPath() {
 	// Path distance: 20
 }
 
+func ExampleLabeledAdjacencyList_DijkstraLabels() {
+	// labels are arc weights.  there are parallel arcs from 0 to 1.
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 1, Label: 2}, {To: 2, Label: 6}},
+		1: {{To: 2, Label: 3}},
+		2: {},
+		3: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, dist, labels := g.DijkstraLabels(0, w)
+	fmt.Println("dist:", dist)
+	fmt.Println("path to 2:", f.PathToLabeled(2, labels))
+	// Output:
+	// dist: [0 2 5 +Inf]
+	// path to 2: [{{0 1} 2} {{1 2} 3}]
+}
+
 func ExampleLabeledAdjacencyList_Dijkstra_allPaths() {
 	// arcs are directed right:
 	//       -----------------------
@@ -479,6 +544,306 @@ func ExampleLabeledAdjacencyList_Dijkstra_allPaths() {
 	// 5:     [2 5]                   2     2
 }
 
+func ExampleLabeledDirected_ReachableWithin() {
+	// arcs are directed right:
+	//       -----------------------
+	//      /      (wt: 14)         \
+	//     /                         \
+	//    /     (9)           (2)     \
+	//   0-------------2---------------5
+	//    \           / \             /
+	//     \     (10)/   \(11)    (9)/
+	//   (7)\       /     \         /
+	//       ------1-------3-------4
+	//               (15)     (6)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 7}, {To: 2, Label: 9}, {To: 5, Label: 14}},
+		1: {{To: 2, Label: 10}, {To: 3, Label: 15}},
+		2: {{To: 3, Label: 11}, {To: 5, Label: 2}},
+		3: {{To: 4, Label: 6}},
+		4: {{To: 5, Label: 9}},
+		5: {},
+	}}
+	w := func(label graph.LI) float64 { return float64(label) }
+	// shortest distance to node 5 is 11
+	fmt.Println(g.ReachableWithin(0, 10, w).Slice())
+	fmt.Println(g.ReachableWithin(0, 11, w).Slice())
+	// Output:
+	// [0 1 2]
+	// [0 1 2 5]
+}
+
+func ExampleLabeledDirected_ShortestPathDAG() {
+	// arcs directed right, all weights 1 except as shown
+	//      1
+	//     / \
+	//    0   3--4
+	//     \ / (2)
+	//      2------5
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 1}},
+		1: {{To: 3, Label: 1}},
+		2: {{To: 3, Label: 1}, {To: 5, Label: 2}},
+		3: {{To: 4, Label: 1}, {To: 5, Label: 2}},
+		4: {},
+		5: {},
+	}}
+	w := func(label graph.LI) float64 { return float64(label) }
+	d := g.ShortestPathDAG(0, w)
+	fmt.Println(d.AdjacencyList)
+	// Output:
+	// [[1 2] [3] [3 5] [4] [] []]
+}
+
+func TestShortestPathDAG(t *testing.T) {
+	g := r100.l
+	w := func(l graph.LI) float64 { return r100.w[l] }
+	d := g.ShortestPathDAG(r100.start, w)
+	if c, _, _ := d.Cyclic(); c {
+		t.Fatal("cyclic")
+	}
+	_, dist, _ := g.Dijkstra(r100.start, -1, w)
+	for fr, to := range d.AdjacencyList {
+		for _, to := range to {
+			found := false
+			for _, h := range g.LabeledAdjacencyList[fr] {
+				if h.To == to && dist[fr]+w(h.Label) == dist[to] {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatal("arc", fr, to, "not tight")
+			}
+		}
+	}
+}
+
+func ExampleLabeledAdjacencyList_Dijkstra_unreachable() {
+	//    (3)    (4)
+	//   0--->1--->2    3
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}},
+		1: {{To: 2, Label: 4}},
+		3: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	f, dist, n := g.Dijkstra(0, -1, w)
+	fmt.Println(n, "nodes reached")
+	for nd, d := range dist {
+		fmt.Println(nd, d, f.Paths[nd].From)
+	}
+	// Output:
+	// 3 nodes reached
+	// 0 0 -1
+	// 1 3 0
+	// 2 7 1
+	// 3 +Inf -1
+}
+
+func ExampleLabeledAdjacencyList_Johnson() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 2, Label: -1}},
+		1: {{To: 3, Label: -2}},
+		2: {{To: 1, Label: 4}, {To: 3, Label: 3}},
+		3: {{To: 0, Label: 2}},
+		4: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	d, neg := g.Johnson(w)
+	for _, di := range d {
+		fmt.Printf("%2.0f\n", di)
+	}
+	fmt.Println("negative cycle:", neg)
+	g[3][0].Label = -2
+	d, neg = g.Johnson(w)
+	fmt.Println(d, "negative cycle:", neg)
+	// Output:
+	// [ 0  3 -1  1 +Inf]
+	// [ 0  0 -1 -2 +Inf]
+	// [ 4  4  0  2 +Inf]
+	// [ 2  5  1  0 +Inf]
+	// [+Inf +Inf +Inf +Inf  0]
+	// negative cycle: false
+	// [] negative cycle: true
+}
+
+func TestJohnson(t *testing.T) {
+	// random DAGs with negative weights, compare to FloydWarshall
+	r := rand.New(rand.NewSource(59))
+	for i := 0; i < 20; i++ {
+		n := 2 + r.Intn(30)
+		g := make(graph.LabeledAdjacencyList, n)
+		var wt []float64
+		for fr := 0; fr < n; fr++ {
+			for to := fr + 1; to < n; to++ {
+				if r.Intn(4) == 0 {
+					g[fr] = append(g[fr], graph.Half{
+						To:    graph.NI(to),
+						Label: graph.LI(len(wt))})
+					wt = append(wt, float64(r.Intn(16)-5))
+				}
+			}
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		want := g.FloydWarshall(w)
+		got, neg := g.Johnson(w)
+		if neg {
+			t.Fatal("negative cycle reported for DAG")
+		}
+		for fr := range want {
+			for to := range want {
+				if math.Abs(got[fr][to]-want[fr][to]) > 1e-9 &&
+					got[fr][to] != want[fr][to] {
+					t.Fatal(fr, to, got[fr][to], want[fr][to])
+				}
+			}
+		}
+	}
+}
+
+func ExampleLabeledDirected_KShortestPaths() {
+	// labels are arc weights
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 4}, {To: 4, Label: 1}, {To: 5, Label: 2}},
+		2: {{To: 4, Label: 3}},
+		3: {{To: 5, Label: 2}},
+		4: {{To: 5, Label: 2}},
+		5: {},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	paths, dists := g.KShortestPaths(0, 5, 10, w)
+	for i, p := range paths {
+		fmt.Println(p, dists[i])
+	}
+	// Output:
+	// [0 1 5] 5
+	// [0 1 4 5] 6
+	// [0 2 4 5] 7
+	// [0 1 3 5] 9
+}
+
+// allSimplePathDists enumerates distances of all loopless paths from
+// start to end.
+func allSimplePathDists(g graph.LabeledAdjacencyList, start, end graph.NI, w graph.WeightFunc) (d []float64) {
+	on := make([]bool, len(g))
+	var f func(n graph.NI, dist float64)
+	f = func(n graph.NI, dist float64) {
+		if n == end {
+			d = append(d, dist)
+			return
+		}
+		on[n] = true
+		// least weight arc to each neighbor, as paths are node sequences
+		best := map[graph.NI]float64{}
+		for _, nb := range g[n] {
+			if b, ok := best[nb.To]; !ok || w(nb.Label) < b {
+				best[nb.To] = w(nb.Label)
+			}
+		}
+		for to, wt := range best {
+			if !on[to] {
+				f(to, dist+wt)
+			}
+		}
+		on[n] = false
+	}
+	f(start, 0)
+	sort.Float64s(d)
+	return
+}
+
+func TestKShortestPaths(t *testing.T) {
+	r := rand.New(rand.NewSource(60))
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(7)
+		g := make(graph.LabeledAdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(r.Intn(n)),
+				Label: graph.LI(r.Intn(10))})
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		start, end := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+		k := 1 + r.Intn(8)
+		paths, dists := graph.LabeledDirected{g}.KShortestPaths(start, end, k, w)
+		want := allSimplePathDists(g, start, end, w)
+		if len(want) > k {
+			want = want[:k]
+		}
+		if fmt.Sprint(dists) != fmt.Sprint(want) && len(want) > 0 {
+			t.Fatal("dists", dists, "want", want)
+		}
+		if len(paths) != len(want) {
+			t.Fatal(len(paths), "paths, want", len(want))
+		}
+		seen := map[string]bool{}
+		for x, p := range paths {
+			if p[0] != start || p[len(p)-1] != end {
+				t.Fatal("path", p, "not", start, "to", end)
+			}
+			on := map[graph.NI]bool{}
+			for _, n := range p {
+				if on[n] {
+					t.Fatal("path", p, "has loop")
+				}
+				on[n] = true
+			}
+			if seen[fmt.Sprint(p)] {
+				t.Fatal("duplicate path", p)
+			}
+			seen[fmt.Sprint(p)] = true
+			if x > 0 && dists[x] < dists[x-1] {
+				t.Fatal("dists not increasing", dists)
+			}
+		}
+	}
+}
+
+func TestDijkstraLabels(t *testing.T) {
+	r := rand.New(rand.NewSource(61))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(20)
+		g := make(graph.LabeledAdjacencyList, n)
+		for j := r.Intn(4 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(r.Intn(n)),
+				Label: graph.LI(r.Intn(10))})
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		start := graph.NI(r.Intn(n))
+		f, dist, labels := g.DijkstraLabels(start, w)
+		_, want, _ := g.Dijkstra(start, -1, w)
+		if fmt.Sprint(dist) != fmt.Sprint(want) {
+			t.Fatal("dist", dist, "want", want)
+		}
+		// labeled arcs of each path must exist and sum to dist
+		for to := range g {
+			if f.Paths[to].Len == 0 {
+				continue
+			}
+			sum := 0.
+			for _, e := range f.PathToLabeled(graph.NI(to), labels) {
+				found := false
+				for _, nb := range g[e.N1] {
+					if nb.To == e.N2 && nb.Label == e.LI {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatal("arc", e, "not in graph")
+				}
+				sum += w(e.LI)
+			}
+			if sum != dist[to] {
+				t.Fatal("path to", to, "sums to", sum, "want", dist[to])
+			}
+		}
+	}
+}
+
 func TestSSSP(t *testing.T) {
 	testSSSP(r100, t)
 }
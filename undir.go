@@ -6,7 +6,13 @@ package graph
 // undir.go has methods specific to undirected graphs, Undirected and
 // LabeledUndirected.
 
-import "errors"
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
 
 // AddEdge adds an edge to a graph.
 //
@@ -52,6 +58,559 @@ func ArcDensity(n, a int) float64 {
 	return float64(a) / (float64(n) * float64(n-1))
 }
 
+// ArticulationPoints finds the articulation points, or cut nodes, of g.
+//
+// An articulation point is a node whose removal would disconnect its
+// connected component.  Disconnected graphs are handled, each connected
+// component being searched from its own root.  The root of each search tree
+// is an articulation point only if it has more than one child.
+//
+// The result is sorted by node number.  See also CutStructure, which finds
+// both articulation points and bridges in a single traversal.
+func (g Undirected) ArticulationPoints() []NI {
+	_, a := g.CutStructure()
+	return a.Slice()
+}
+
+// BiconnectedComponents decomposes g into maximal biconnected components.
+//
+// Each component of result bcc is a list of edges.  A bridge forms a
+// component of a single edge.  Nodes with no edges do not appear in any
+// edge list and are returned separately in result isolated so that the
+// decomposition covers all nodes of g.
+//
+// Components are found with TarjanBiconnectedComponents, which uses the
+// edge-stack variant of the depth-first low-link method.  As with that
+// method, g must be a simple graph.
+func (g Undirected) BiconnectedComponents() (bcc [][]Edge, isolated []NI) {
+	g.TarjanBiconnectedComponents(func(c []Edge) bool {
+		bcc = append(bcc, c)
+		return true
+	})
+	for n, to := range g.AdjacencyList {
+		if len(to) == 0 {
+			isolated = append(isolated, NI(n))
+		}
+	}
+	return
+}
+
+// BlockCutTree constructs the block-cut tree of g.
+//
+// Blocks are the biconnected components of g as found by
+// TarjanBiconnectedComponents.  As with that method, g must be a simple graph.
+//
+// See type BlockCutTree.
+func (g Undirected) BlockCutTree() *BlockCutTree {
+	a := g.AdjacencyList
+	t := &BlockCutTree{
+		up: make([]int, len(a)),
+	}
+	for n := range t.up {
+		t.up[n] = -1
+	}
+	nb := make([][]int, len(a)) // blocks containing each node
+	g.TarjanBiconnectedComponents(func(bcc []Edge) bool {
+		b := len(t.Blocks)
+		var m Bits
+		var nodes []NI
+		for _, e := range bcc {
+			for _, n := range []NI{e.N1, e.N2} {
+				if m.Bit(n) == 0 {
+					m.SetBit(n, 1)
+					nodes = append(nodes, n)
+					nb[n] = append(nb[n], b)
+				}
+			}
+		}
+		t.Blocks = append(t.Blocks, nodes)
+		return true
+	})
+	for n, bs := range nb {
+		if len(bs) > 1 {
+			t.Cuts.SetBit(NI(n), 1)
+		}
+	}
+	// root each tree of the forest and record parents
+	t.blockUp = make([]NI, len(t.Blocks))
+	var vis Bits // visited blocks
+	var df func(b int)
+	df = func(b int) {
+		vis.SetBit(NI(b), 1)
+		for _, n := range t.Blocks[b] {
+			if t.Cuts.Bit(n) == 0 {
+				t.up[n] = b
+				continue
+			}
+			if t.up[n] >= 0 {
+				continue // n is parent of b
+			}
+			t.up[n] = b
+			for _, c := range nb[n] {
+				if vis.Bit(NI(c)) == 0 {
+					t.blockUp[c] = n
+					df(c)
+				}
+			}
+		}
+	}
+	for b := range t.Blocks {
+		if vis.Bit(NI(b)) == 0 {
+			t.blockUp[b] = -1
+			df(b)
+		}
+	}
+	return t
+}
+
+// Bridges finds the bridges of g.
+//
+// A bridge is an edge whose removal would disconnect its connected
+// component.  Each bridge is reported once, with N1 < N2.  The result is
+// sorted by N1, then N2.  Loops are never bridges and an edge with a
+// parallel edge is not a bridge.
+//
+// See also CutStructure, which finds both bridges and articulation points
+// in a single traversal.
+func (g Undirected) Bridges() []Edge {
+	b, _ := g.CutStructure()
+	return b
+}
+
+// CartesianProduct constructs the Cartesian product of g and h.
+//
+// The product has a node for each pair of nodes (u, v) where u is a node of
+// g and v is a node of h.  Returned function nodeAt gives the node number
+// of the product for a pair, u*len(h.AdjacencyList)+v.  Nodes (u, v) and
+// (u', v') are adjacent if u = u' and v is adjacent to v' in h or if v = v'
+// and u is adjacent to u' in g.
+//
+// For example the Cartesian product of two paths is a grid and the product
+// of two cycles is a torus.
+//
+// See also TensorProduct.
+func (g Undirected) CartesianProduct(h Undirected) (p Undirected, nodeAt func(u, v NI) NI) {
+	ga, ha := g.AdjacencyList, h.AdjacencyList
+	nodeAt = func(u, v NI) NI { return u*NI(len(ha)) + v }
+	pa := make(AdjacencyList, len(ga)*len(ha))
+	for u, gto := range ga {
+		for v, hto := range ha {
+			n := nodeAt(NI(u), NI(v))
+			for _, u2 := range gto {
+				pa[n] = append(pa[n], nodeAt(u2, NI(v)))
+			}
+			for _, v2 := range hto {
+				pa[n] = append(pa[n], nodeAt(NI(u), v2))
+			}
+		}
+	}
+	return Undirected{pa}, nodeAt
+}
+
+// ChromaticNumber finds the chromatic number of g, the minimum number of
+// colors needed for a proper coloring, and a coloring with that number
+// of colors.
+//
+// The search is by branch and bound.  The result of ColorGreedy gives an
+// initial upper bound and the size of a maximum clique gives a lower bound.
+// Loops are ignored.  The time can be exponential in the number of nodes.
+// It is practical for graphs up to around 20 nodes.
+//
+// In result colors, colors[n] is the color of node n, a number from 0 to k-1.
+func (g Undirected) ChromaticNumber() (k int, colors []NI) {
+	colors, k = g.ColorGreedy()
+	a := g.loopFree().AdjacencyList
+	clique := g.MaxClique() // lower bound
+	if k <= len(clique) {
+		return
+	}
+	// color clique nodes first, then others by decreasing degree
+	c := make([]NI, len(a))
+	for n := range c {
+		c[n] = -1
+	}
+	var order []NI
+	for i, n := range clique {
+		c[n] = NI(i)
+	}
+	ord := degreeOrder{make([]NI, len(a)), make([]int, len(a))}
+	for n := range a {
+		ord.nodes[n] = NI(n)
+		ord.deg[n] = len(a[n])
+	}
+	sort.Stable(ord)
+	for _, n := range ord.nodes {
+		if c[n] < 0 {
+			order = append(order, n)
+		}
+	}
+	var bb func(i, used int)
+	bb = func(i, used int) {
+		if i == len(order) {
+			k = used
+			copy(colors, c)
+			return
+		}
+		n := order[i]
+	colorLoop:
+		for col := NI(0); int(col) <= used && int(col) < k-1; col++ {
+			for _, to := range a[n] {
+				if c[to] == col {
+					continue colorLoop
+				}
+			}
+			c[n] = col
+			u := used
+			if int(col) == used {
+				u++
+			}
+			bb(i+1, u)
+			c[n] = -1
+			if k == len(clique) {
+				return // can't do better than lower bound
+			}
+		}
+	}
+	bb(0, len(clique))
+	return
+}
+
+// loopFree returns g if it has no loops, otherwise a copy of g without loops.
+func (g Undirected) loopFree() Undirected {
+	if l, _ := g.HasLoop(); !l {
+		return g
+	}
+	c := make(AdjacencyList, len(g.AdjacencyList))
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if to != NI(fr) {
+				c[fr] = append(c[fr], to)
+			}
+		}
+	}
+	return Undirected{c}
+}
+
+// ClusteringCoefficient computes local clustering coefficients of nodes of g
+// and their average.
+//
+// The local clustering coefficient of a node is the fraction of pairs of its
+// neighbors that are themselves neighbors, that is, the number of triangles
+// through the node divided by the number possible.  Nodes with fewer than
+// two neighbors have a local coefficient of 0.  Result global is the average
+// of the local coefficients over all nodes of g, or 0 for a graph with no
+// nodes.  Note this average is not the same as the ratio of triangles to
+// connected triples, sometimes also called the global clustering
+// coefficient.
+//
+// Loops and parallel edges are ignored, g is treated as a simple graph.
+func (g Undirected) ClusteringCoefficient() (local []float64, global float64) {
+	nbs := g.neighborSets()
+	local = make([]float64, len(nbs))
+	var common Bits
+	for n, nb := range nbs {
+		d := nb.PopCount()
+		if d < 2 {
+			continue
+		}
+		links := 0 // count of links among neighbors, each counted twice
+		nb.Iterate(func(to NI) bool {
+			common.And(nb, nbs[to])
+			links += common.PopCount()
+			return true
+		})
+		local[n] = float64(links) / float64(d*(d-1))
+		global += local[n]
+	}
+	if len(local) > 0 {
+		global /= float64(len(local))
+	}
+	return
+}
+
+// neighborSets returns the set of neighbors of each node of g, excluding
+// the node itself.
+func (g Undirected) neighborSets() []Bits {
+	nbs := make([]Bits, len(g.AdjacencyList))
+	for n, to := range g.AdjacencyList {
+		for _, to := range to {
+			if to != NI(n) {
+				nbs[n].SetBit(to, 1)
+			}
+		}
+	}
+	return nbs
+}
+
+// ColorGreedy colors the nodes of g using the Welsh-Powell heuristic.
+//
+// Nodes are considered in order of decreasing degree, ties broken by node
+// number, and each is assigned the smallest color not already assigned to
+// a neighbor.  The result is a proper coloring, no two adjacent nodes have
+// the same color, but the number of colors is not necessarily minimal.
+// Loops are ignored, both for coloring and in the degrees used for ordering.
+//
+// In result colors, colors[n] is the color of node n, a number from 0 to
+// nColors-1.
+func (g Undirected) ColorGreedy() (colors []NI, nColors int) {
+	a := g.loopFree().AdjacencyList
+	ord := degreeOrder{make([]NI, len(a)), make([]int, len(a))}
+	for n := range a {
+		ord.nodes[n] = NI(n)
+		ord.deg[n] = len(a[n])
+	}
+	sort.Stable(ord)
+	colors = make([]NI, len(a))
+	for n := range colors {
+		colors[n] = -1
+	}
+	var used Bits // colors used by neighbors
+	for _, n := range ord.nodes {
+		used.Clear()
+		for _, to := range a[n] {
+			if c := colors[to]; c >= 0 {
+				used.SetBit(c, 1)
+			}
+		}
+		c := NI(0)
+		for used.Bit(c) == 1 {
+			c++
+		}
+		colors[n] = c
+		if int(c) == nColors {
+			nColors++
+		}
+	}
+	return
+}
+
+// degreeOrder sorts nodes by decreasing degree.
+type degreeOrder struct {
+	nodes []NI
+	deg   []int // indexed by node
+}
+
+func (d degreeOrder) Len() int { return len(d.nodes) }
+func (d degreeOrder) Less(i, j int) bool {
+	return d.deg[d.nodes[i]] > d.deg[d.nodes[j]]
+}
+func (d degreeOrder) Swap(i, j int) { d.nodes[i], d.nodes[j] = d.nodes[j], d.nodes[i] }
+
+// Complement returns the complement of g.
+//
+// The result has the nodes of g and an edge between each pair of distinct
+// nodes not adjacent in g.  The result is a simple graph, with neighbors of
+// each node listed in increasing order.  Loops and parallel edges of g are
+// ignored.
+func (g Undirected) Complement() Undirected {
+	nbs := g.neighborSets()
+	c := make(AdjacencyList, len(nbs))
+	for n1, nb := range nbs {
+		for n2 := range nbs {
+			if n2 != n1 && nb.Bit(NI(n2)) == 0 {
+				c[n1] = append(c[n1], NI(n2))
+			}
+		}
+	}
+	return Undirected{c}
+}
+
+// ContractEdge contracts the edge between nodes a and b, merging node b
+// into node a.
+//
+// The result c has one fewer node than g.  Result nodeMap maps each node of
+// g to its node in c.  Node b maps to the same node as a; nodes numbered
+// greater than b are renumbered one less.  Edges incident to b become edges
+// incident to the merged node.  Edges between a and b are removed rather
+// than becoming loops.  Loops already present at a or b are kept.
+//
+// If parallel is true, parallel edges are kept, as needed for multigraph
+// contraction.  If parallel is false, edges of the merged node are
+// deduplicated so that it has at most one edge to any node.
+//
+// Nodes a and b must be distinct but need not be adjacent.
+func (g Undirected) ContractEdge(a, b NI, parallel bool) (c Undirected, nodeMap []NI) {
+	nodeMap = make([]NI, len(g.AdjacencyList))
+	for n := range nodeMap {
+		nodeMap[n] = NI(n)
+		if NI(n) > b {
+			nodeMap[n]--
+		}
+	}
+	nodeMap[b] = nodeMap[a]
+	m := nodeMap[a]
+	ca := make(AdjacencyList, len(nodeMap)-1)
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if NI(fr) == a && to == b || NI(fr) == b && to == a {
+				continue
+			}
+			ca[nodeMap[fr]] = append(ca[nodeMap[fr]], nodeMap[to])
+		}
+	}
+	if !parallel {
+		// deduplicate edges of the merged node m
+		var seen Bits
+		k := ca[m][:0]
+		for _, to := range ca[m] {
+			if seen.Bit(to) == 0 {
+				seen.SetBit(to, 1)
+				k = append(k, to)
+			}
+		}
+		ca[m] = k
+		for fr, to := range ca {
+			if NI(fr) == m {
+				continue
+			}
+			found := false
+			k := to[:0]
+			for _, to := range to {
+				if to == m {
+					if found {
+						continue
+					}
+					found = true
+				}
+				k = append(k, to)
+			}
+			ca[fr] = k
+		}
+	}
+	return Undirected{ca}, nodeMap
+}
+
+// CoreNumbers computes the core number, or coreness, of each node of g.
+//
+// The core number of a node is the largest k for which the node belongs
+// to the k-core of g.  See KCore.  Degree is as computed by Degree, so a
+// loop counts twice and parallel edges each count.
+//
+// Nodes are removed in order of least remaining degree, the algorithm of
+// Batagelj and Zaversnik, in time O(n+m).  See also Degeneracy.
+func (g Undirected) CoreNumbers() (core []int) {
+	a := g.AdjacencyList
+	core = make([]int, len(a))
+	deg := make([]int, len(a))
+	var bucket [][]NI // nodes by degree, entries may be stale
+	for n := range a {
+		d := g.Degree(NI(n))
+		deg[n] = d
+		for len(bucket) <= d {
+			bucket = append(bucket, nil)
+		}
+		bucket[d] = append(bucket[d], NI(n))
+	}
+	var removed Bits
+	k := 0
+	for d, nr := 0, 0; nr < len(a); {
+		b := bucket[d]
+		if len(b) == 0 {
+			d++
+			continue
+		}
+		last := len(b) - 1
+		n := b[last]
+		bucket[d] = b[:last]
+		if removed.Bit(n) == 1 || deg[n] != d {
+			continue // stale entry
+		}
+		removed.SetBit(n, 1)
+		nr++
+		if d > k {
+			k = d
+		}
+		core[n] = k
+		for _, to := range a[n] {
+			// degrees are not reduced below k, the current core number
+			if removed.Bit(to) == 0 && deg[to] > k {
+				deg[to]--
+				bucket[deg[to]] = append(bucket[deg[to]], to)
+				if deg[to] < d {
+					d = deg[to]
+				}
+			}
+		}
+	}
+	return
+}
+
+// CutStructure finds the bridges and articulation points of g.
+//
+// A bridge is an edge whose removal would disconnect its connected
+// component.  An articulation point, or cut node, is a node whose removal
+// would disconnect its connected component.  Both are found in a single
+// depth-first traversal.
+//
+// Bridges are returned with N1 < N2, sorted by N1, then N2.  Loops are
+// never bridges and an edge with a parallel edge is not a bridge.
+//
+// See also ArticulationPoints and Bridges.
+func (g Undirected) CutStructure() (bridges []Edge, articulationPoints Bits) {
+	a := g.AdjacencyList
+	num := make([]int, len(a)) // preorder number, 0 means not visited
+	low := make([]int, len(a)) // low-link
+	x := 0
+	var df func(n, fr NI)
+	df = func(n, fr NI) {
+		x++
+		num[n] = x
+		low[n] = x
+		skip := fr >= 0 // skip a single arc back to fr
+		children := 0
+		for _, to := range a[n] {
+			switch {
+			case num[to] == 0:
+				children++
+				df(to, n)
+				if low[to] < low[n] {
+					low[n] = low[to]
+				}
+				if low[to] > num[n] {
+					if n < to {
+						bridges = append(bridges, Edge{n, to})
+					} else {
+						bridges = append(bridges, Edge{to, n})
+					}
+				}
+				if fr >= 0 && low[to] >= num[n] {
+					articulationPoints.SetBit(n, 1)
+				}
+			case to == fr && skip:
+				skip = false
+			case num[to] < low[n]:
+				low[n] = num[to]
+			}
+		}
+		if fr < 0 && children > 1 {
+			articulationPoints.SetBit(n, 1)
+		}
+	}
+	for n := range a {
+		if num[n] == 0 {
+			df(NI(n), -1)
+		}
+	}
+	sort.Sort(EdgeList(bridges))
+	return
+}
+
+// DegreeHistogram returns the degree distribution of g.
+//
+// In the result, hist[d] is the number of nodes with degree d.  As with
+// method Degree, loops count twice.  The length of the result is one more
+// than the maximum degree.
+func (g Undirected) DegreeHistogram() (hist []int) {
+	for n := range g.AdjacencyList {
+		d := g.Degree(NI(n))
+		for len(hist) <= d {
+			hist = append(hist, 0)
+		}
+		hist[d]++
+	}
+	return
+}
+
 // Density returns density for a simple undirected graph.
 //
 // Parameter n is order, or number of nodes of a simple undirected graph.
@@ -63,6 +622,71 @@ func Density(n, m int) float64 {
 	return float64(m) * 2 / (float64(n) * float64(n-1))
 }
 
+// EdgeBetweenness computes the betweenness centrality of each edge of g.
+//
+// The betweenness of an edge is the sum, over all unordered pairs of nodes,
+// of the fraction of shortest paths between the pair that pass through the
+// edge.  Paths are shortest by number of edges.
+//
+// Edges are keyed in the result with N1 < N2, or N1 == N2 for loops.  Loops
+// are on no shortest path and have no entry in the result.  Parallel edges
+// share a single entry.
+//
+// The method implements Brandes' algorithm, with a breadth first search
+// from each node.  Repeatedly removing an edge of highest betweenness gives
+// the Girvan-Newman community detection algorithm.
+func (g Undirected) EdgeBetweenness() map[Edge]float64 {
+	a := g.AdjacencyList
+	eb := map[Edge]float64{}
+	dist := make([]int, len(a))
+	sigma := make([]float64, len(a)) // number of shortest paths
+	delta := make([]float64, len(a)) // dependency
+	pred := make([][]NI, len(a))
+	var order []NI
+	for s := range a {
+		for n := range a {
+			dist[n] = -1
+			sigma[n] = 0
+			delta[n] = 0
+			pred[n] = pred[n][:0]
+		}
+		dist[s] = 0
+		sigma[s] = 1
+		order = append(order[:0], NI(s))
+		for i := 0; i < len(order); i++ {
+			v := order[i]
+			for _, w := range a[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					order = append(order, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					pred[w] = append(pred[w], v)
+				}
+			}
+		}
+		// accumulate dependencies in order of decreasing distance
+		for i := len(order) - 1; i > 0; i-- {
+			w := order[i]
+			for _, v := range pred[w] {
+				c := sigma[v] / sigma[w] * (1 + delta[w])
+				delta[v] += c
+				e := Edge{v, w}
+				if w < v {
+					e = Edge{w, v}
+				}
+				eb[e] += c
+			}
+		}
+	}
+	// each pair was counted from both ends
+	for e, b := range eb {
+		eb[e] = b / 2
+	}
+	return eb
+}
+
 // EulerianCycleD for undirected graphs is a bit of an experiment.
 //
 // It is about the same as the directed version, but modified for an undirected
@@ -93,6 +717,360 @@ func (g Undirected) EulerianCycleD(m int) ([]NI, error) {
 	return e.p, nil
 }
 
+// EulerianPath finds an Eulerian path in an undirected multigraph.
+//
+// The method first checks degree conditions.  All nodes must have even
+// degree, in which case the path will be a cycle, or exactly two nodes must
+// have odd degree, in which case the path starts at one of them.  Nodes with
+// no edges are ignored; all other nodes must be connected.
+//
+// * If g has no nodes, result is nil, nil.
+//
+// * If g has an Eulerian path, result is an Eulerian path with err = nil.
+// The path result is a list of nodes.
+//
+// * Otherwise, result is nil, error
+//
+// Internally, EulerianPath copies the entire graph g.
+// See EulerianPathD for a more space efficient version.
+func (g Undirected) EulerianPath() ([]NI, error) {
+	if len(g.AdjacencyList) == 0 {
+		return nil, nil
+	}
+	start := NI(-1)
+	odd := 0
+	for n, to := range g.AdjacencyList {
+		if g.Degree(NI(n))%2 == 1 {
+			if odd == 0 {
+				start = NI(n)
+			}
+			odd++
+		} else if start < 0 && len(to) > 0 {
+			start = NI(n)
+		}
+	}
+	if odd > 2 {
+		return nil, errors.New("no Eulerian path")
+	}
+	if start < 0 {
+		start = 0 // no edges
+	}
+	c, _ := g.Copy()
+	return c.EulerianPathD(g.Size(), start)
+}
+
+// EulerianPathD finds an Eulerian path in an undirected multigraph.
+//
+// EulerianPathD is destructive on its receiver g.  See EulerianPath for
+// a non-destructive version.
+//
+// Argument m must be the correct size, or number of edges in g.
+// Argument start must be a valid start node for the path.
+//
+// * If g has no nodes, result is nil, nil.
+//
+// * If g has an Eulerian path, result is an Eulerian path with err = nil.
+// The path result is a list of nodes, where the first node is start.
+//
+// * Otherwise, result is nil, error
+func (g Undirected) EulerianPathD(m int, start NI) ([]NI, error) {
+	if len(g.AdjacencyList) == 0 {
+		return nil, nil
+	}
+	e := newEulerian(g.AdjacencyList, m)
+	// nodes with no edges need not be visited
+	for n, to := range g.AdjacencyList {
+		if len(to) == 0 && NI(n) != start {
+			e.uv.SetBit(NI(n), 0)
+		}
+	}
+	e.p[0] = start
+	// the first path doesn't have be a cycle.
+	e.pushUndir()
+	e.keep()
+	for e.s >= 0 {
+		start = e.top()
+		e.pushUndir()
+		// paths after the first must be cycles
+		if e.top() != start {
+			return nil, errors.New("no Eulerian path")
+		}
+		e.keep()
+	}
+	if !e.uv.Zero() {
+		return nil, errors.New("no Eulerian path")
+	}
+	return e.p, nil
+}
+
+// KCore returns the k-core of g, the maximal subgraph in which every node
+// has degree at least k.
+//
+// The k-core is found by repeatedly removing nodes of degree less than k.
+// Result core has bits set for the surviving nodes.  The result is empty
+// if no nodes survive.  See CoreNumbers for the treatment of loops and
+// parallel edges.
+func (g Undirected) KCore(k int) (core Bits) {
+	for n, c := range g.CoreNumbers() {
+		if c >= k {
+			core.SetBit(NI(n), 1)
+		}
+	}
+	return
+}
+
+// KargerMinCut finds a minimum cut of g by Karger's randomized contraction
+// algorithm.
+//
+// A cut is a partition of the nodes of g into two non-empty sets.  The size
+// of a cut is the number of edges with ends in different sets.  Parallel
+// edges each count, loops are ignored.
+//
+// Each trial contracts randomly chosen edges until two merged nodes remain,
+// these then defining a cut.  Contraction is done with a DisjointSet rather
+// than by ContractEdge, as only the node sets are needed.  The method runs
+// the given number of trials, at least one, and returns the smallest cut
+// found.  Result partition has bits set for the nodes of one side of the cut.
+//
+// The result is a minimum cut only with some probability.  A single trial
+// finds any given minimum cut with probability at least 2/(n(n-1)) for
+// a graph of n nodes.  Running n(n-1)/2 * ln n trials, or O(n² log n),
+// finds a minimum cut with probability at least 1 - 1/n.  See
+// LabeledUndirected.GlobalMinCut for a deterministic algorithm.
+//
+// If g is not connected, the result is a cut of size 0 separating one
+// connected component.  If g has fewer than two nodes, the result is a cut
+// size of 0 and an empty partition.
+//
+// Random numbers are drawn from src.  If src is nil, the method creates
+// a time-seeded source for one-time use.
+func (g Undirected) KargerMinCut(trials int, src rand.Source) (cutSize int, partition Bits) {
+	a := g.AdjacencyList
+	if len(a) < 2 {
+		return 0, partition
+	}
+	r := newRand(src)
+	var edges []Edge
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to {
+				edges = append(edges, Edge{NI(fr), to})
+			}
+		}
+	}
+	cutSize = -1
+	for ; ; trials-- {
+		ds := NewDisjointSet(len(a))
+		nSets := len(a)
+		for _, x := range r.Perm(len(edges)) {
+			if nSets == 2 {
+				break
+			}
+			if e := edges[x]; ds.union(e.N1, e.N2) {
+				nSets--
+			}
+		}
+		// if g is disconnected, nSets may be greater than 2.  the set
+		// of node 0 is then one side of a cut of size 0.
+		c := 0
+		for _, e := range edges {
+			if ds.find(e.N1) != ds.find(e.N2) {
+				c++
+			}
+		}
+		if cutSize < 0 || c < cutSize {
+			cutSize = c
+			partition.Clear()
+			r0 := ds.find(0)
+			for n := range a {
+				if ds.find(NI(n)) == r0 {
+					partition.SetBit(NI(n), 1)
+				}
+			}
+		}
+		if trials <= 1 || cutSize == 0 {
+			return
+		}
+	}
+}
+
+// LineGraph constructs the line graph of g.
+//
+// The line graph has a node for each edge of g.  Two nodes of the line
+// graph are linked by an edge if the corresponding edges of g share an end
+// point.  In result edgeMap, edgeMap[n] is the edge of g corresponding to
+// node n of lg.  Edges are numbered in order of their N1 end, then in the
+// order of arcs from N1, with N1 <= N2.
+//
+// Parallel edges of g are distinct nodes of lg, linked by a single edge.
+// A loop of g is a node of lg linked to the other edges at its node.
+// The result lg is a simple graph.
+func (g Undirected) LineGraph() (lg Undirected, edgeMap []Edge) {
+	a := g.AdjacencyList
+	inc := make([][]NI, len(a)) // lg nodes incident on each node of g
+	for fr, to := range a {
+		for _, to := range to {
+			if to < NI(fr) {
+				continue
+			}
+			e := NI(len(edgeMap))
+			edgeMap = append(edgeMap, Edge{NI(fr), to})
+			inc[fr] = append(inc[fr], e)
+			if to != NI(fr) {
+				inc[to] = append(inc[to], e)
+			}
+		}
+	}
+	lg.AdjacencyList = make(AdjacencyList, len(edgeMap))
+	linked := make([]Bits, len(edgeMap))
+	for _, es := range inc {
+		for i, e1 := range es {
+			for _, e2 := range es[i+1:] {
+				if linked[e1].Bit(e2) == 0 {
+					linked[e1].SetBit(e2, 1)
+					linked[e2].SetBit(e1, 1)
+					lg.AddEdge(e1, e2)
+				}
+			}
+		}
+	}
+	return
+}
+
+// MaxClique returns a maximum clique of g, a clique with the largest number
+// of nodes.
+//
+// Where multiple maximum cliques exist, the one returned is the first found
+// by MaximalCliques.  The result is nil for a graph with no nodes.
+func (g Undirected) MaxClique() (c []NI) {
+	l := g.loopFree()
+	l.BronKerbosch3(l.BKPivotMaxDegree, func(m []NI) bool {
+		if len(m) > len(c) {
+			c = m
+		}
+		return true
+	})
+	return
+}
+
+// MaximalCliques returns all maximal cliques of g.
+//
+// The method uses BronKerbosch3, the Bron-Kerbosch algorithm with pivoting
+// and degeneracy ordering.  Unlike the BronKerbosch methods, loops are
+// allowed in g and are ignored.  Disconnected graphs are allowed.  Each
+// clique is returned as a sorted list of nodes.
+func (g Undirected) MaximalCliques() (cliques [][]NI) {
+	l := g.loopFree()
+	l.BronKerbosch3(l.BKPivotMaxDegree, func(c []NI) bool {
+		cliques = append(cliques, c)
+		return true
+	})
+	return
+}
+
+// MaximalIndependentSet finds a maximal independent set of g.
+//
+// An independent set is a set of nodes, no two of which are adjacent.  It is
+// maximal if no other node can be added.  The set found is not necessarily
+// a maximum independent set.
+//
+// The method is greedy, repeatedly adding the remaining node with lowest
+// degree among remaining nodes, ties broken by node number, then removing
+// it and its neighbors.  A node with a loop is adjacent to itself and so is
+// never included.
+func (g Undirected) MaximalIndependentSet() (set Bits) {
+	a := g.AdjacencyList
+	var rem Bits
+	rem.SetAll(len(a))
+	for n, to := range a {
+		for _, to := range to {
+			if to == NI(n) {
+				rem.SetBit(to, 0)
+			}
+		}
+	}
+	deg := make([]int, len(a)) // degree among remaining nodes
+	for n, to := range a {
+		for _, to := range to {
+			if rem.Bit(to) == 1 {
+				deg[n]++
+			}
+		}
+	}
+	remove := func(n NI) {
+		rem.SetBit(n, 0)
+		for _, to := range a[n] {
+			deg[to]--
+		}
+	}
+	for !rem.Zero() {
+		min := NI(-1)
+		rem.Iterate(func(n NI) bool {
+			if min < 0 || deg[n] < deg[min] {
+				min = n
+			}
+			return true
+		})
+		set.SetBit(min, 1)
+		remove(min)
+		for _, to := range a[min] {
+			if rem.Bit(to) == 1 {
+				remove(to)
+			}
+		}
+	}
+	return
+}
+
+// PercolationProfile computes the size of the largest connected component
+// as nodes are removed from g.
+//
+// Nodes are removed in the order given by argument order, which must not
+// contain duplicate nodes.  Element i of the result is the number of nodes
+// in the largest connected component remaining after removing nodes
+// order[0] through order[i].  Nodes not in order are never removed.
+//
+// Rather than removing nodes, the method adds them back in reverse order
+// using a disjoint set data structure.  The time complexity is nearly linear
+// in the size of the graph.
+func (g Undirected) PercolationProfile(order []NI) []int {
+	a := g.AdjacencyList
+	var present, removed Bits
+	for _, n := range order {
+		removed.SetBit(n, 1)
+	}
+	ds := NewDisjointSet(len(a))
+	size := make([]int, len(a)) // component size, valid for root nodes
+	max := 0
+	add := func(n NI) {
+		present.SetBit(n, 1)
+		size[n] = 1
+		for _, to := range a[n] {
+			if present.Bit(to) == 0 {
+				continue
+			}
+			rn, rt := ds.find(n), ds.find(to)
+			if ds.union(rn, rt) {
+				size[ds.find(n)] = size[rn] + size[rt]
+			}
+		}
+		if s := size[ds.find(n)]; s > max {
+			max = s
+		}
+	}
+	for n := range a {
+		if removed.Bit(NI(n)) == 0 {
+			add(NI(n))
+		}
+	}
+	p := make([]int, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		p[i] = max
+		add(order[i])
+	}
+	return p
+}
+
 // TarjanBiconnectedComponents decomposes a graph into maximal biconnected
 // components, components for which if any node were removed the component
 // would remain connected.
@@ -159,6 +1137,213 @@ func (g Undirected) TarjanBiconnectedComponents(emit func([]Edge) bool) {
 	}
 }
 
+// TensorProduct constructs the tensor product of g and h.
+//
+// The product has a node for each pair of nodes (u, v) where u is a node of
+// g and v is a node of h.  Returned function nodeAt gives the node number
+// of the product for a pair, as with CartesianProduct.  Nodes (u, v) and
+// (u', v') are adjacent if u is adjacent to u' in g and v is adjacent to v'
+// in h.  The tensor product is also known as the categorical, direct, or
+// Kronecker product.
+//
+// See also CartesianProduct.
+func (g Undirected) TensorProduct(h Undirected) (p Undirected, nodeAt func(u, v NI) NI) {
+	ga, ha := g.AdjacencyList, h.AdjacencyList
+	nodeAt = func(u, v NI) NI { return u*NI(len(ha)) + v }
+	pa := make(AdjacencyList, len(ga)*len(ha))
+	for u, gto := range ga {
+		for v, hto := range ha {
+			n := nodeAt(NI(u), NI(v))
+			for _, u2 := range gto {
+				for _, v2 := range hto {
+					pa[n] = append(pa[n], nodeAt(u2, v2))
+				}
+			}
+		}
+	}
+	return Undirected{pa}, nodeAt
+}
+
+// TriangleCount counts triangles in g.
+//
+// Result total is the number of triangles, or 3-cliques, in g.  Result
+// perNode gives the number of triangles each node is part of.  Loops and
+// parallel edges are ignored, g is treated as a simple graph.
+//
+// The algorithm is a node iterator.  Neighbor lists are sorted and each
+// triangle n1 < n2 < n3 is found once by merging the neighbors of n1 and n2
+// greater than n2.
+func (g Undirected) TriangleCount() (total int, perNode []int) {
+	a := g.AdjacencyList
+	// sorted neighbor lists, without loops or duplicates
+	nbs := make([]NodeList, len(a))
+	for n, to := range a {
+		s := append(NodeList{}, to...)
+		sort.Sort(s)
+		u := s[:0]
+		for _, to := range s {
+			if to != NI(n) && (len(u) == 0 || to != u[len(u)-1]) {
+				u = append(u, to)
+			}
+		}
+		nbs[n] = u
+	}
+	perNode = make([]int, len(a))
+	for n1, nb1 := range nbs {
+		for x, n2 := range nb1 {
+			if n2 < NI(n1) {
+				continue
+			}
+			// merge the rest of nb1 with the part of nb2 greater than n2
+			r1 := nb1[x+1:]
+			r2 := nbs[n2]
+			for len(r2) > 0 && r2[0] <= n2 {
+				r2 = r2[1:]
+			}
+			for len(r1) > 0 && len(r2) > 0 {
+				switch {
+				case r1[0] < r2[0]:
+					r1 = r1[1:]
+				case r1[0] > r2[0]:
+					r2 = r2[1:]
+				default:
+					total++
+					perNode[n1]++
+					perNode[n2]++
+					perNode[r1[0]]++
+					r1 = r1[1:]
+					r2 = r2[1:]
+				}
+			}
+		}
+	}
+	return
+}
+
+// UniformSpanningTree constructs a spanning tree chosen uniformly at random
+// from all spanning trees of g.
+//
+// The method implements Wilson's algorithm, building the tree from
+// loop-erased random walks.  The tree is rooted at node 0 and returned as
+// a FromList with Len, Leaves, and MaxLen valid.
+//
+// Argument r is the random source.  If r is nil, a source is seeded from
+// the current time.
+//
+// An error is returned if g is not connected.
+func (g Undirected) UniformSpanningTree(r *rand.Rand) (*FromList, error) {
+	if !g.IsConnected() {
+		return nil, errors.New("not connected")
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	a := g.AdjacencyList
+	f := NewFromList(len(a))
+	if len(a) == 0 {
+		return &f, nil
+	}
+	p := f.Paths
+	inTree := NewBits(0)
+	p[0].From = -1
+	next := make([]NI, len(a))
+	for i := range a {
+		// random walk from i until hitting the tree, then add the
+		// loop-erased path to the tree
+		a.lastExitWalk(NI(i), inTree, next, r)
+		for u := NI(i); inTree.Bit(u) == 0; u = next[u] {
+			inTree.SetBit(u, 1)
+			p[u].From = next[u]
+		}
+	}
+	f.RecalcLeaves()
+	f.RecalcLen()
+	return &f, nil
+}
+
+// VertexCover2Approx finds a vertex cover of g no more than twice the size
+// of a minimum vertex cover.
+//
+// A vertex cover is a set of nodes such that every edge of g is incident
+// to at least one node of the set.  The method takes edges in order and for
+// each edge not yet covered, adds both end points to the cover.  A loop is
+// covered by its single node.
+func (g Undirected) VertexCover2Approx() (cover Bits) {
+	for fr, to := range g.AdjacencyList {
+		if cover.Bit(NI(fr)) == 1 {
+			continue
+		}
+		for _, to := range to {
+			if cover.Bit(to) == 0 {
+				cover.SetBit(NI(fr), 1)
+				cover.SetBit(to, 1)
+				break
+			}
+		}
+	}
+	return
+}
+
+// BlockCutTree represents the block-cut tree of an undirected graph.
+//
+// Blocks are the biconnected components of the graph.  Cut nodes, or
+// articulation points, are the nodes in more than one block.  The tree
+// has a node for each block and each cut node, with an edge between a block
+// and each cut node it contains.  For a graph that is not connected it is a
+// forest.
+//
+// Construct with Undirected.BlockCutTree.
+type BlockCutTree struct {
+	Blocks [][]NI // nodes of each block
+	Cuts   Bits   // cut nodes
+	// the tree, rooted.  For a node that is not a cut node, up is the
+	// block containing it.  For a cut node it is the parent block.
+	// Isolated nodes have up = -1.  blockUp is the parent cut node of each
+	// block, or -1 for a root block.
+	up      []int
+	blockUp []NI
+}
+
+// SameBiconnectedComponent returns true if nodes u and v lie on a common
+// simple cycle.
+//
+// This is true when u and v are in the same block and the block is not a
+// single bridge edge.  The query takes constant time.
+//
+// SameBiconnectedComponent returns true for u == v.
+func (t *BlockCutTree) SameBiconnectedComponent(u, v NI) bool {
+	if u == v {
+		return true
+	}
+	bu, bv := t.up[u], t.up[v]
+	if bu < 0 || bv < 0 {
+		return false
+	}
+	b := -1 // block containing u and v
+	switch cu, cv := t.Cuts.Bit(u) == 1, t.Cuts.Bit(v) == 1; {
+	case !cu && !cv:
+		if bu == bv {
+			b = bu
+		}
+	case cu && !cv:
+		if bu == bv || t.blockUp[bv] == u {
+			b = bv
+		}
+	case !cu && cv:
+		if bu == bv || t.blockUp[bu] == v {
+			b = bu
+		}
+	default:
+		switch {
+		case bu == bv, t.blockUp[bu] == v:
+			b = bu
+		case t.blockUp[bv] == u:
+			b = bv
+		}
+	}
+	return b >= 0 && len(t.Blocks[b]) > 2
+}
+
 /* half-baked.  Read the 72 paper.  Maybe revisit at some point.
 type BiconnectedComponents struct {
 	Graph  AdjacencyList
@@ -274,6 +1459,95 @@ func (p *LabeledUndirected) AddEdge(e Edge, l LI) {
 	}
 }
 
+// GlobalMinCut finds a minimum weight cut of g by the Stoer-Wagner
+// algorithm.
+//
+// A cut is a partition of the nodes of g into two non-empty sets.  The
+// weight of a cut is the sum of weights of edges with ends in different
+// sets.  Edge weights are returned by w and must be non-negative.  Parallel
+// edges each count, loops are ignored.  Result partition has bits set for
+// the nodes of one side of a cut of minimum weight among all cuts.
+//
+// If g is not connected, the result is a cut of weight 0.  If g has fewer
+// than two nodes, the result is a cut weight of 0 and an empty partition.
+//
+// The implementation uses an adjacency matrix and takes time O(n³) for
+// a graph of n nodes.  See Undirected.KargerMinCut for a randomized
+// algorithm on unweighted graphs.
+func (g LabeledUndirected) GlobalMinCut(w WeightFunc) (cutWeight float64, partition Bits) {
+	a := g.LabeledAdjacencyList
+	n := len(a)
+	if n < 2 {
+		return 0, partition
+	}
+	m := make([][]float64, n) // weights between merged nodes
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to.To {
+				wt := w(to.Label)
+				m[fr][to.To] += wt
+				m[to.To][fr] += wt
+			}
+		}
+	}
+	group := make([][]NI, n) // original nodes of each merged node
+	active := make([]NI, n)  // merged nodes remaining
+	for i := range group {
+		group[i] = []NI{NI(i)}
+		active[i] = NI(i)
+	}
+	cutWeight = math.Inf(1)
+	conn := make([]float64, n) // connectivity to the growing set
+	added := make([]bool, n)
+	for len(active) > 1 {
+		// minimum cut phase
+		for _, v := range active {
+			conn[v] = 0
+			added[v] = false
+		}
+		var s, t NI = -1, -1
+		for _ = range active {
+			// add the most tightly connected node
+			x := NI(-1)
+			for _, v := range active {
+				if !added[v] && (x < 0 || conn[v] > conn[x]) {
+					x = v
+				}
+			}
+			added[x] = true
+			s, t = t, x
+			for _, v := range active {
+				conn[v] += m[x][v]
+			}
+		}
+		// cut of the phase separates t from the rest
+		if c := conn[t]; c < cutWeight {
+			cutWeight = c
+			partition.Clear()
+			for _, n := range group[t] {
+				partition.SetBit(n, 1)
+			}
+		}
+		// merge t into s
+		for _, v := range active {
+			m[s][v] += m[t][v]
+			m[v][s] = m[s][v]
+		}
+		m[s][s] = 0
+		group[s] = append(group[s], group[t]...)
+		for x, v := range active {
+			if v == t {
+				active = append(active[:x], active[x+1:]...)
+				break
+			}
+		}
+	}
+	return
+}
+
 // TarjanBiconnectedComponents decomposes a graph into maximal biconnected
 // components, components for which if any node were removed the component
 // would remain connected.
@@ -314,6 +314,34 @@ func (g LabeledUndirected) BronKerbosch3(pivot func(P, X *Bits) NI, emit func([]
 	}
 }
 
+// ComponentSubgraph returns the connected component of g containing node rep
+// as an independent graph.
+//
+// Nodes of the component are renumbered to 0 through len(nodeMap)-1,
+// preserving their relative order.  In result nodeMap, nodeMap[n] is the
+// node of g corresponding to node n of sub.  Arcs of sub are in the same
+// order as in g.
+//
+// See also InducedSubgraph.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) ComponentSubgraph(rep NI) (sub LabeledUndirected, nodeMap []NI) {
+	a := g.LabeledAdjacencyList
+	var c Bits
+	var df func(NI)
+	df = func(n NI) {
+		c.SetBit(n, 1)
+		for _, nb := range a[n] {
+			if c.Bit(nb.To) == 0 {
+				df(nb.To)
+			}
+		}
+	}
+	df(rep)
+	s, nodeMap := a.InducedSubgraph(c.Slice())
+	return LabeledUndirected{s}, nodeMap
+}
+
 // ConnectedComponentBits returns a function that iterates over connected
 // components of g, returning a member bitmap for each.
 //
@@ -355,6 +383,39 @@ func (g LabeledUndirected) ConnectedComponentBits() func() (order int, bits Bits
 	}
 }
 
+// ConnectedComponentLabels labels each node of g with the connected component
+// it belongs to.
+//
+// In result label, label[n] is the component number of node n, a number
+// from 0 to nComponents-1.  Components are numbered in order of their lowest
+// numbered node, the same order as representatives returned by
+// ConnectedComponentReps.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) ConnectedComponentLabels() (label []int, nComponents int) {
+	a := g.LabeledAdjacencyList
+	label = make([]int, len(a))
+	for n := range label {
+		label[n] = -1
+	}
+	var df func(NI)
+	df = func(n NI) {
+		label[n] = nComponents
+		for _, nb := range a[n] {
+			if label[nb.To] < 0 {
+				df(nb.To)
+			}
+		}
+	}
+	for n := range a {
+		if label[n] < 0 {
+			df(NI(n))
+			nComponents++
+		}
+	}
+	return
+}
+
 // ConnectedComponentLists returns a function that iterates over connected
 // components of g, returning the member list of each.
 //
@@ -464,6 +525,7 @@ func (g LabeledUndirected) Degeneracy() (k int, ordering []NI, cores []int) {
 		}
 		D[dv] = append(D[dv], NI(v))
 	}
+	cores = []int{0} // grown as k increases
 	for ox := range a {
 		// find a non-empty D
 		i := 0
@@ -175,6 +175,27 @@ func ExampleLabeledUndirected_BronKerbosch3() {
 	// [1 2 5]
 }
 
+func ExampleLabeledUndirected_ComponentSubgraph() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 3}, 10)
+	g.AddEdge(graph.Edge{0, 4}, 11)
+	g.AddEdge(graph.Edge{3, 4}, 12)
+	g.AddEdge(graph.Edge{1, 5}, 13)
+	sub, nodeMap := g.ComponentSubgraph(4)
+	fmt.Println("node map:", nodeMap)
+	for fr, to := range sub.LabeledAdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// node map: [0 3 4]
+	// 0 [{1 10} {2 11}]
+	// 1 [{0 10} {2 12}]
+	// 2 [{0 11} {1 12}]
+}
+
 func ExampleLabeledUndirected_ConnectedComponentBits() {
 	//    0   1   2
 	//   / \   \
@@ -198,6 +219,25 @@ func ExampleLabeledUndirected_ConnectedComponentBits() {
 	// 1  000100
 }
 
+func ExampleLabeledUndirected_ConnectedComponentLabels() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 3}, 0)
+	g.AddEdge(graph.Edge{0, 4}, 0)
+	g.AddEdge(graph.Edge{3, 4}, 0)
+	g.AddEdge(graph.Edge{1, 5}, 0)
+	label, n := g.ConnectedComponentLabels()
+	fmt.Println("components:", n)
+	fmt.Println("node: ", []int{0, 1, 2, 3, 4, 5})
+	fmt.Println("label:", label)
+	// Output:
+	// components: 3
+	// node:  [0 1 2 3 4 5]
+	// label: [0 1 2 0 0 1]
+}
+
 func ExampleLabeledUndirected_ConnectedComponentLists() {
 	//    0   1   2
 	//   / \   \
@@ -179,6 +179,27 @@ func ExampleUndirected_BronKerbosch3() {
 	// [1 2 5]
 }
 
+func ExampleUndirected_ComponentSubgraph() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	var g graph.Undirected
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(1, 5)
+	sub, nodeMap := g.ComponentSubgraph(4)
+	fmt.Println("node map:", nodeMap)
+	for fr, to := range sub.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// node map: [0 3 4]
+	// 0 [1 2]
+	// 1 [0 2]
+	// 2 [0 1]
+}
+
 func ExampleUndirected_ConnectedComponentBits() {
 	//    0   1   2
 	//   / \   \
@@ -202,6 +223,25 @@ func ExampleUndirected_ConnectedComponentBits() {
 	// 1  000100
 }
 
+func ExampleUndirected_ConnectedComponentLabels() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	var g graph.Undirected
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(1, 5)
+	label, n := g.ConnectedComponentLabels()
+	fmt.Println("components:", n)
+	fmt.Println("node: ", []int{0, 1, 2, 3, 4, 5})
+	fmt.Println("label:", label)
+	// Output:
+	// components: 3
+	// node:  [0 1 2 3 4 5]
+	// label: [0 1 2 0 0 1]
+}
+
 func ExampleUndirected_ConnectedComponentLists() {
 	//    0   1   2
 	//   / \   \
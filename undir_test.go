@@ -5,6 +5,10 @@ package graph_test
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
 
 	"github.com/soniakeys/graph"
 )
@@ -15,12 +19,672 @@ func ExampleArcDensity() {
 	// 0.25
 }
 
+func ExampleUndirected_BlockCutTree() {
+	// undirected edges:
+	// 3---2---1---7---9
+	//  \ / \ / \   \ /
+	//   4   5---6   8
+	var g graph.Undirected
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	g.AddEdge(2, 1)
+	g.AddEdge(5, 1)
+	g.AddEdge(6, 1)
+	g.AddEdge(6, 5)
+	g.AddEdge(7, 1)
+	g.AddEdge(7, 9)
+	g.AddEdge(7, 8)
+	g.AddEdge(9, 8)
+	t := g.BlockCutTree()
+	fmt.Println("blocks:", t.Blocks)
+	fmt.Println("cuts:", t.Cuts.Slice())
+	for _, p := range []graph.Edge{{3, 4}, {3, 5}, {2, 6}, {1, 7}, {8, 9}, {4, 9}} {
+		fmt.Println(p, t.SameBiconnectedComponent(p.N1, p.N2))
+	}
+	// Output:
+	// blocks: [[4 2 3] [6 1 5 2] [8 7 9] [1 7]]
+	// cuts: [1 2 7]
+	// {3 4} true
+	// {3 5} false
+	// {2 6} true
+	// {1 7} false
+	// {8 9} true
+	// {4 9} false
+}
+
+func TestBlockCutTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g, _, _ := graph.Geometric(30, .2, r)
+		bct := g.BlockCutTree()
+		for u := graph.NI(0); u < 30; u++ {
+			for v := graph.NI(0); v < 30; v++ {
+				// brute force: search blocks
+				want := u == v
+				for _, b := range bct.Blocks {
+					if len(b) > 2 {
+						bs := graph.NewBits(b...)
+						if bs.Bit(u) == 1 && bs.Bit(v) == 1 {
+							want = true
+						}
+					}
+				}
+				if got := bct.SameBiconnectedComponent(u, v); got != want {
+					t.Fatal(u, v, "got", got, "want", want)
+				}
+			}
+		}
+	}
+}
+
+func ExampleUndirected_ArticulationPoints() {
+	// undirected edges:
+	// 3---2---1---7---9   0
+	//  \ / \ / \   \ /
+	//   4   5---6   8     10---11
+	var g graph.Undirected
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	g.AddEdge(2, 1)
+	g.AddEdge(5, 1)
+	g.AddEdge(6, 1)
+	g.AddEdge(6, 5)
+	g.AddEdge(7, 1)
+	g.AddEdge(7, 9)
+	g.AddEdge(7, 8)
+	g.AddEdge(9, 8)
+	g.AddEdge(10, 11)
+	fmt.Println(g.ArticulationPoints())
+	// Output:
+	// [1 2 7]
+}
+
+func ExampleUndirected_Bridges() {
+	// undirected edges:
+	// 0---1---2---3
+	//      \ /
+	//       4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(2, 4)
+	g.AddEdge(5, 4)
+	fmt.Println(g.Bridges())
+	// Output:
+	// [{0 1} {2 3} {4 5}]
+}
+
+func ExampleUndirected_BiconnectedComponents() {
+	// undirected edges:
+	// 0---1---2---3   6
+	//      \ /
+	//       4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(2, 4)
+	g.AddEdge(5, 4)
+	g.AdjacencyList = append(g.AdjacencyList, nil)
+	bcc, isolated := g.BiconnectedComponents()
+	for _, c := range bcc {
+		fmt.Println(c)
+	}
+	fmt.Println("isolated:", isolated)
+	// Output:
+	// [{2 3}]
+	// [{4 5}]
+	// [{4 1} {2 4} {1 2}]
+	// [{0 1}]
+	// isolated: [6]
+}
+
+func ExampleUndirected_CartesianProduct() {
+	// product of paths 0--1--2 and 0--1 is a grid:
+	//
+	//   0--1
+	//   |  |
+	//   2--3
+	//   |  |
+	//   4--5
+	var g, h graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	h.AddEdge(0, 1)
+	p, nodeAt := g.CartesianProduct(h)
+	for fr, to := range p.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	fmt.Println("(2, 1):", nodeAt(2, 1))
+	// Output:
+	// 0 [2 1]
+	// 1 [3 0]
+	// 2 [0 4 3]
+	// 3 [1 5 2]
+	// 4 [2 5]
+	// 5 [3 4]
+	// (2, 1): 5
+}
+
+// TestEdgeless covers graphs where every node has degree 0.  Degeneracy
+// once panicked here, as cores was only allocated when k increased.
+func TestEdgeless(t *testing.T) {
+	for n := 0; n < 4; n++ {
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		k, ord, cores := g.Degeneracy()
+		if k != 0 || len(ord) != n || fmt.Sprint(cores) != fmt.Sprint([]int{n}) {
+			t.Fatal("Degeneracy", n, k, ord, cores)
+		}
+		lg := graph.LabeledUndirected{make(graph.LabeledAdjacencyList, n)}
+		k, ord, cores = lg.Degeneracy()
+		if k != 0 || len(ord) != n || fmt.Sprint(cores) != fmt.Sprint([]int{n}) {
+			t.Fatal("labeled Degeneracy", n, k, ord, cores)
+		}
+		var cliques [][]graph.NI
+		g.BronKerbosch3(g.BKPivotMaxDegree, func(c []graph.NI) bool {
+			cliques = append(cliques, c)
+			return true
+		})
+		if len(cliques) != n {
+			t.Fatal("BronKerbosch3", n, cliques)
+		}
+		for _, c := range cliques {
+			if len(c) != 1 {
+				t.Fatal("BronKerbosch3", n, cliques)
+			}
+		}
+		c := g.MaxClique()
+		if n == 0 && c != nil || n > 0 && len(c) != 1 {
+			t.Fatal("MaxClique", n, c)
+		}
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	// hypercube Q4 is a product of four K2s
+	var k2 graph.Undirected
+	k2.AddEdge(0, 1)
+	q := k2
+	for i := 1; i < 4; i++ {
+		q, _ = q.CartesianProduct(k2)
+	}
+	if len(q.AdjacencyList) != 16 {
+		t.Fatal("order", len(q.AdjacencyList))
+	}
+	for fr, to := range q.AdjacencyList {
+		if len(to) != 4 {
+			t.Fatal("node", fr, "degree", len(to))
+		}
+		for _, to := range to {
+			// neighbors in a hypercube differ in one bit
+			if d := fr ^ int(to); d&(d-1) != 0 {
+				t.Fatal("arc", fr, to)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_ChromaticNumber() {
+	// A crown graph, numbered so that the greedy heuristic does poorly.
+	// Even nodes u and odd nodes v are adjacent unless u+1 == v.
+	var g graph.Undirected
+	for u := 0; u < 8; u += 2 {
+		for v := 1; v < 8; v += 2 {
+			if u+1 != v {
+				g.AddEdge(graph.NI(u), graph.NI(v))
+			}
+		}
+	}
+	_, greedy := g.ColorGreedy()
+	fmt.Println("greedy:", greedy)
+	k, c := g.ChromaticNumber()
+	fmt.Println("chromatic number:", k)
+	fmt.Println(c)
+	// Output:
+	// greedy: 4
+	// chromatic number: 2
+	// [0 1 0 1 0 1 0 1]
+}
+
+func TestChromaticNumber(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(7)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for fr := 1; fr < n; fr++ {
+			for to := 0; to < fr; to++ {
+				if r.Intn(2) == 0 {
+					g.AddEdge(graph.NI(fr), graph.NI(to))
+				}
+			}
+		}
+		k, c := g.ChromaticNumber()
+		proper := func(c []graph.NI) bool {
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if c[fr] == c[to] {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		if !proper(c) {
+			t.Fatal("improper coloring", c)
+		}
+		for _, cn := range c {
+			if cn < 0 || int(cn) >= k {
+				t.Fatal("color", cn, "k", k)
+			}
+		}
+		// brute force: no proper coloring with k-1 colors
+		if k > 1 {
+			b := make([]graph.NI, n)
+			var try func(int) bool
+			try = func(x int) bool {
+				if x == n {
+					return proper(b)
+				}
+				for col := 0; col < k-1; col++ {
+					b[x] = graph.NI(col)
+					if try(x + 1) {
+						return true
+					}
+				}
+				return false
+			}
+			if try(0) {
+				t.Fatal("coloring with", k-1, "colors:", b)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_ClusteringCoefficient() {
+	//   0---1
+	//   |  /|
+	//   | / |
+	//   |/  |
+	//   2---3---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 4) // parallel edge is ignored
+	local, global := g.ClusteringCoefficient()
+	for n, c := range local {
+		fmt.Printf("%d: %.3f\n", n, c)
+	}
+	fmt.Printf("average: %.3f\n", global)
+	// Output:
+	// 0: 1.000
+	// 1: 0.667
+	// 2: 0.667
+	// 3: 0.333
+	// 4: 0.000
+	// average: 0.533
+}
+
+func ExampleUndirected_ColorGreedy() {
+	//   0---1
+	//   |\  |
+	//   | \ |
+	//   |  \|
+	//   3---2---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(4, 4) // loops are ignored
+	fmt.Println(g.ColorGreedy())
+	// Output:
+	// [1 2 0 2 1] 3
+}
+
+func TestColorGreedy(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for i := 0; i < 20; i++ {
+		g, _, _ := graph.Geometric(50, .3, r)
+		c, nc := g.ColorGreedy()
+		for fr, to := range g.AdjacencyList {
+			if c[fr] < 0 || int(c[fr]) >= nc {
+				t.Fatal("node", fr, "color", c[fr], "of", nc)
+			}
+			for _, to := range to {
+				if c[fr] == c[to] {
+					t.Fatal("adjacent nodes", fr, to, "color", c[fr])
+				}
+			}
+		}
+	}
+}
+
+func TestColorGreedy_loops(t *testing.T) {
+	// loops must not change the result, including by changing node order
+	r := rand.New(rand.NewSource(29))
+	for i := 0; i < 20; i++ {
+		g, _, _ := graph.Geometric(30, .3, r)
+		want, wn := g.ColorGreedy()
+		for n := range g.AdjacencyList {
+			if r.Intn(3) == 0 {
+				g.AddEdge(graph.NI(n), graph.NI(n))
+			}
+		}
+		got, gn := g.ColorGreedy()
+		if gn != wn {
+			t.Fatal("with loops", gn, "colors, without", wn)
+		}
+		for n := range want {
+			if got[n] != want[n] {
+				t.Fatal("node", n, "with loops", got[n], "without", want[n])
+			}
+		}
+	}
+}
+
+func ExampleUndirected_Complement() {
+	//   0---1
+	//   |
+	//   2   3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(3, 3) // loop is ignored
+	for fr, to := range g.Complement().AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [3]
+	// 1 [2 3]
+	// 2 [1 3]
+	// 3 [0 1 2]
+}
+
+func ExampleUndirected_ContractEdge() {
+	//   0---1
+	//   |   |
+	//   3---2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	// merge nodes 0 and 2, which are not adjacent.  the merged node
+	// has parallel edges to nodes 1 and 3, as renumbered.
+	c, nodeMap := g.ContractEdge(0, 2, true)
+	fmt.Println("node map:", nodeMap)
+	for n, to := range c.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println()
+	// the same, but merging parallel edges
+	c, _ = g.ContractEdge(0, 2, false)
+	for n, to := range c.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// node map: [0 1 0 2]
+	// 0 [1 2 1 2]
+	// 1 [0 0]
+	// 2 [0 0]
+	//
+	// 0 [1 2]
+	// 1 [0]
+	// 2 [0]
+}
+
+func TestContractEdge(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(10)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		a := graph.NI(r.Intn(n))
+		b := graph.NI(r.Intn(n - 1))
+		if b >= a {
+			b++
+		}
+		ab := 0
+		for _, to := range g.AdjacencyList[a] {
+			if to == b {
+				ab++
+			}
+		}
+		for _, parallel := range []bool{true, false} {
+			c, m := g.ContractEdge(a, b, parallel)
+			if len(c.AdjacencyList) != n-1 || m[a] != m[b] {
+				t.Fatal("order", len(c.AdjacencyList), "map", m)
+			}
+			if ok, _, _ := c.IsUndirected(); !ok {
+				t.Fatal("not undirected")
+			}
+			if parallel && c.Size() != g.Size()-ab {
+				t.Fatal("size", c.Size(), "want", g.Size()-ab)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_CoreNumbers() {
+	//   0---1
+	//   |\ /|
+	//   | X |---4---5
+	//   |/ \|
+	//   3---2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	fmt.Println(g.CoreNumbers())
+	// Output:
+	// [3 3 3 3 1 1]
+}
+
+// naiveKCore removes nodes of degree < k until none remain.
+func naiveKCore(g graph.Undirected, k int) (core graph.Bits) {
+	a := g.AdjacencyList
+	core.SetAll(len(a))
+	for again := true; again; {
+		again = false
+		for n, to := range a {
+			if core.Bit(graph.NI(n)) == 0 {
+				continue
+			}
+			d := 0
+			for _, to := range to {
+				if core.Bit(to) == 1 {
+					d++
+					if to == graph.NI(n) {
+						d++
+					}
+				}
+			}
+			if d < k {
+				core.SetBit(graph.NI(n), 0)
+				again = true
+			}
+		}
+	}
+	return
+}
+
+func TestKCore(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		for m := r.Intn(3 * n); m > 0; m-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		cn := g.CoreNumbers()
+		max := 0
+		for _, c := range cn {
+			if c > max {
+				max = c
+			}
+		}
+		for k := 0; k <= max+1; k++ {
+			got := g.KCore(k)
+			if want := naiveKCore(g, k); !got.Eq(want) {
+				t.Fatal(k, "core", got.Slice(), "want", want.Slice())
+			}
+		}
+	}
+}
+
+func ExampleUndirected_CutStructure() {
+	// undirected edges:
+	// 3---2---1---7---9
+	//  \ / \ / \   \ /
+	//   4   5---6   8
+	var g graph.Undirected
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	g.AddEdge(2, 1)
+	g.AddEdge(5, 1)
+	g.AddEdge(6, 1)
+	g.AddEdge(6, 5)
+	g.AddEdge(7, 1)
+	g.AddEdge(7, 9)
+	g.AddEdge(7, 8)
+	g.AddEdge(9, 8)
+	b, a := g.CutStructure()
+	fmt.Println("bridges:", b)
+	fmt.Println("articulation points:", a.Slice())
+	// Output:
+	// bridges: [{1 7}]
+	// articulation points: [1 2 7]
+}
+
+// nComponents counts connected components of g, ignoring node xn and a
+// single edge xe.
+func nComponents(g graph.Undirected, xn graph.NI, xe graph.Edge) int {
+	var b graph.Bits
+	var df func(graph.NI)
+	df = func(n graph.NI) {
+		b.SetBit(n, 1)
+		skip := true
+		for _, to := range g.AdjacencyList[n] {
+			if skip && (n == xe.N1 && to == xe.N2 || n == xe.N2 && to == xe.N1) {
+				skip = false
+				continue
+			}
+			if to != xn && b.Bit(to) == 0 {
+				df(to)
+			}
+		}
+	}
+	c := 0
+	for n := range g.AdjacencyList {
+		if graph.NI(n) != xn && b.Bit(graph.NI(n)) == 0 {
+			c++
+			df(graph.NI(n))
+		}
+	}
+	return c
+}
+
+func TestUndirected_CutStructure(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g, _, _ := graph.Geometric(30, .2, r)
+		// add some parallel edges and loops
+		for j := 0; j < 3; j++ {
+			n := graph.NI(r.Intn(30))
+			if to := g.AdjacencyList[n]; len(to) > 0 {
+				g.AddEdge(n, to[0])
+			}
+			g.AddEdge(n, n)
+		}
+		b, a := g.CutStructure()
+		c := nComponents(g, -1, graph.Edge{-1, -1})
+		var wantA graph.Bits
+		var wantB []graph.Edge
+		for n, to := range g.AdjacencyList {
+			if nComponents(g, graph.NI(n), graph.Edge{-1, -1}) > c {
+				wantA.SetBit(graph.NI(n), 1)
+			}
+			for _, to := range to {
+				if graph.NI(n) < to &&
+					nComponents(g, -1, graph.Edge{graph.NI(n), to}) > c {
+					wantB = append(wantB, graph.Edge{graph.NI(n), to})
+				}
+			}
+		}
+		if !a.Eq(wantA) {
+			t.Fatal("articulation points", a.Slice(), "want", wantA.Slice())
+		}
+		sort.Sort(graph.EdgeList(wantB))
+		if fmt.Sprint(b) != fmt.Sprint(wantB) {
+			t.Fatal("bridges", b, "want", wantB)
+		}
+	}
+}
+
 func ExampleDensity() {
 	fmt.Println(graph.Density(4, 3))
 	// Output:
 	// 0.5
 }
 
+func ExampleUndirected_EdgeBetweenness() {
+	// barbell graph
+	//   0       4
+	//   |\     /|
+	//   | 2---3 |
+	//   |/     \|
+	//   1       5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 5)
+	g.AddEdge(4, 5)
+	eb := g.EdgeBetweenness()
+	var es []graph.Edge
+	for e := range eb {
+		es = append(es, e)
+	}
+	sort.Sort(graph.EdgeList(es))
+	for _, e := range es {
+		fmt.Println(e, eb[e])
+	}
+	// Output:
+	// {0 1} 1
+	// {0 2} 4
+	// {1 2} 4
+	// {2 3} 9
+	// {3 4} 4
+	// {3 5} 4
+	// {4 5} 1
+}
+
 func ExampleUndirected_EulerianCycleD() {
 	var g graph.Undirected
 	// add 6 edges
@@ -38,6 +702,301 @@ func ExampleUndirected_EulerianCycleD() {
 	// [0 1 2 2 1 2 0] <nil>
 }
 
+func ExampleUndirected_DegreeHistogram() {
+	//   0---1---2
+	//       |
+	//       3---4   5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(5, 5) // loop counts twice
+	fmt.Println(g.DegreeHistogram())
+	// Output:
+	// [0 3 2 1]
+}
+
+func ExampleUndirected_EulerianPath() {
+	//   0
+	//  / \
+	// 1---2
+	//  \ /
+	//   3   4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AdjacencyList = append(g.AdjacencyList, nil) // isolated node 4
+	fmt.Println(g.EulerianPath())
+	g.AddEdge(1, 2) // now all degrees even
+	fmt.Println(g.EulerianPath())
+	g.AddEdge(3, 4)
+	fmt.Println(g.EulerianPath())
+	g.AddEdge(5, 6) // disconnected
+	fmt.Println(g.EulerianPath())
+	// Output:
+	// [1 0 2 3 1 2] <nil>
+	// [0 1 2 1 3 2 0] <nil>
+	// [3 1 0 2 1 2 3 4] <nil>
+	// [] no Eulerian path
+}
+
+func TestUndirected_EulerianPath(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 200; i++ {
+		var g graph.Undirected
+		n := 1 + r.Intn(6)
+		for m := r.Intn(10); m > 0; m-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		p, err := g.EulerianPath()
+		if err != nil {
+			continue
+		}
+		// path must use each edge exactly once
+		m := g.Size()
+		if len(g.AdjacencyList) > 0 && len(p) != m+1 {
+			t.Fatal("path", p, "edges", m)
+		}
+		c, _ := g.Copy()
+		for x := 1; x < len(p); x++ {
+			fr, to := p[x-1], p[x]
+			ok, y := c.HasArc(fr, to)
+			if !ok {
+				t.Fatal("path", p, "uses missing edge", fr, to, g.AdjacencyList)
+			}
+			a := c.AdjacencyList[fr]
+			c.AdjacencyList[fr] = append(a[:y:y], a[y+1:]...)
+			if fr != to {
+				_, y = c.HasArc(to, fr)
+				a = c.AdjacencyList[to]
+				c.AdjacencyList[to] = append(a[:y:y], a[y+1:]...)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_KCore() {
+	//   0---1
+	//   |\ /|
+	//   | X |---4---5
+	//   |/ \|
+	//   3---2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	fmt.Println(g.KCore(2).Slice())
+	fmt.Println(g.KCore(3).Slice())
+	fmt.Println(g.KCore(4).Slice())
+	// Output:
+	// [0 1 2 3]
+	// [0 1 2 3]
+	// []
+}
+
+func ExampleUndirected_KargerMinCut() {
+	// two triangles joined by a single edge
+	//
+	//   0       3
+	//   |\     /|
+	//   | 2---4 |
+	//   |/     \|
+	//   1       5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(5, 3)
+	c, p := g.KargerMinCut(50, rand.NewSource(1))
+	fmt.Println("cut size:", c)
+	fmt.Println("partition:", p.Slice())
+	// Output:
+	// cut size: 1
+	// partition: [0 1 2]
+}
+
+// bruteMinCutSize enumerates all partitions.
+func bruteMinCutSize(g graph.Undirected) int {
+	a := g.AdjacencyList
+	best := -1
+	for m := 1; m < 1<<uint(len(a))-1; m++ {
+		c := 0
+		for fr, to := range a {
+			for _, to := range to {
+				if graph.NI(fr) < to && (m>>uint(fr)&1) != (m>>uint(to)&1) {
+					c++
+				}
+			}
+		}
+		if best < 0 || c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+func TestKargerMinCut(t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	for i := 0; i < 50; i++ {
+		n := 2 + r.Intn(8)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(4 * n); j > 0; j-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		// many more trials than n² log n, for confidence
+		c, p := g.KargerMinCut(10*n*n*n, r)
+		if want := bruteMinCutSize(g); c != want {
+			t.Fatal("cut", c, "want", want)
+		}
+		if pc := p.PopCount(); pc == 0 || pc == n {
+			t.Fatal("partition", p.Slice())
+		}
+		x := 0
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if graph.NI(fr) < to && p.Bit(graph.NI(fr)) != p.Bit(to) {
+					x++
+				}
+			}
+		}
+		if x != c {
+			t.Fatal("partition crosses", x, "edges, cut size", c)
+		}
+	}
+}
+
+func ExampleUndirected_LineGraph() {
+	//      0
+	//     / \
+	//    1---2---3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	lg, edgeMap := g.LineGraph()
+	for n, e := range edgeMap {
+		fmt.Println(n, e, lg.AdjacencyList[n])
+	}
+	// Output:
+	// 0 {0 1} [1 2]
+	// 1 {0 2} [0 2 3]
+	// 2 {1 2} [0 1 3]
+	// 3 {2 3} [1 2]
+}
+
+func TestLineGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(31))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(10)
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		for m := r.Intn(2 * n); m > 0; m-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		lg, edgeMap := g.LineGraph()
+		if len(edgeMap) != g.Size() {
+			t.Fatal(len(edgeMap), "edges mapped, graph size", g.Size())
+		}
+		if s, _ := lg.IsSimple(); !s {
+			t.Fatal("line graph not simple")
+		}
+		for x, e1 := range edgeMap {
+			for y, e2 := range edgeMap {
+				share := x != y && (e1.N1 == e2.N1 || e1.N1 == e2.N2 ||
+					e1.N2 == e2.N1 || e1.N2 == e2.N2)
+				if has, _ := lg.HasArc(graph.NI(x), graph.NI(y)); has != share {
+					t.Fatal("edges", e1, e2, "share end point", share,
+						"linked", has)
+				}
+			}
+		}
+	}
+}
+
+func ExampleUndirected_MaxClique() {
+	//   0---1   5---6
+	//   |\ /|    \ /
+	//   | X |     7
+	//   |/ \|
+	//   3---2---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(4, 4)
+	g.AddEdge(5, 6)
+	g.AddEdge(5, 7)
+	g.AddEdge(6, 7)
+	fmt.Println(g.MaxClique())
+	// Output:
+	// [0 1 2 3]
+}
+
+func ExampleUndirected_MaximalCliques() {
+	//   0---1   5---6
+	//   |\ /|    \ /
+	//   | X |     7
+	//   |/ \|
+	//   3---2---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(4, 4) // loops are ignored
+	g.AddEdge(5, 6)
+	g.AddEdge(5, 7)
+	g.AddEdge(6, 7)
+	for _, c := range g.MaximalCliques() {
+		fmt.Println(c)
+	}
+	// Output:
+	// [2 4]
+	// [5 6 7]
+	// [0 1 2 3]
+}
+
+func ExampleUndirected_PercolationProfile() {
+	//     1   2
+	//      \ /
+	//   4---0---3
+	//           |
+	//           5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 5)
+	// remove center first
+	fmt.Println(g.PercolationProfile([]graph.NI{0, 1, 2, 3, 4, 5}))
+	// remove leaves first
+	fmt.Println(g.PercolationProfile([]graph.NI{1, 2, 5, 4, 0, 3}))
+	// Output:
+	// [2 2 2 1 1 0]
+	// [5 4 3 2 1 0]
+}
+
 func ExampleUndirected_TarjanBiconnectedComponents() {
 	// undirected edges:
 	// 3---2---1---7---9
@@ -82,6 +1041,87 @@ func ExampleUndirected_TarjanBiconnectedComponents() {
 	// {1 7}
 }
 
+func ExampleLabeledUndirected_GlobalMinCut() {
+	// two triangles joined by two light edges.  edge labels are weights.
+	//
+	//   0       3
+	//   |\     /|
+	//   | 2-1-4 |
+	//   |/     \|
+	//   1---2---5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 5)
+	g.AddEdge(graph.Edge{1, 2}, 5)
+	g.AddEdge(graph.Edge{2, 0}, 5)
+	g.AddEdge(graph.Edge{2, 4}, 1)
+	g.AddEdge(graph.Edge{1, 5}, 2)
+	g.AddEdge(graph.Edge{3, 4}, 4)
+	g.AddEdge(graph.Edge{4, 5}, 4)
+	g.AddEdge(graph.Edge{5, 3}, 4)
+	w := func(l graph.LI) float64 { return float64(l) }
+	c, p := g.GlobalMinCut(w)
+	fmt.Println("cut weight:", c)
+	fmt.Println("partition:", p.Slice())
+	// Output:
+	// cut weight: 3
+	// partition: [3 4 5]
+}
+
+// bruteMinCutWeight enumerates all partitions.
+func bruteMinCutWeight(g graph.LabeledUndirected, w graph.WeightFunc) float64 {
+	a := g.LabeledAdjacencyList
+	best := math.Inf(1)
+	for m := 1; m < 1<<uint(len(a))-1; m++ {
+		c := 0.
+		for fr, to := range a {
+			for _, to := range to {
+				if graph.NI(fr) < to.To &&
+					(m>>uint(fr)&1) != (m>>uint(to.To)&1) {
+					c += w(to.Label)
+				}
+			}
+		}
+		if c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+func TestGlobalMinCut(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 200; i++ {
+		n := 2 + r.Intn(8)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for j := r.Intn(4 * n); j > 0; j-- {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(10)))
+		}
+		c, p := g.GlobalMinCut(w)
+		if want := bruteMinCutWeight(g, w); c != want {
+			t.Fatal(g, "cut weight", c, "want", want)
+		}
+		// partition must be a proper cut of the returned weight
+		if k := p.PopCount(); k == 0 || k == n {
+			t.Fatal(g, "partition", p.Slice())
+		}
+		pc := 0.
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, to := range to {
+				if graph.NI(fr) < to.To &&
+					p.Bit(graph.NI(fr)) != p.Bit(to.To) {
+					pc += w(to.Label)
+				}
+			}
+		}
+		if pc != c {
+			t.Fatal(g, "partition", p.Slice(), "weight", pc, "want", c)
+		}
+	}
+}
+
 func ExampleLabeledUndirected_TarjanBiconnectedComponents() {
 	// undirected edges:
 	// 3---2---1---7---9
@@ -126,6 +1166,282 @@ func ExampleLabeledUndirected_TarjanBiconnectedComponents() {
 	// {1 7}
 }
 
+func ExampleUndirected_TensorProduct() {
+	// product of K2 0--1 with path 0--1--2
+	var g, h graph.Undirected
+	g.AddEdge(0, 1)
+	h.AddEdge(0, 1)
+	h.AddEdge(1, 2)
+	p, nodeAt := g.TensorProduct(h)
+	for fr, to := range p.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	fmt.Println("(1, 2):", nodeAt(1, 2))
+	// Output:
+	// 0 [4]
+	// 1 [3 5]
+	// 2 [4]
+	// 3 [1]
+	// 4 [0 2]
+	// 5 [1]
+	// (1, 2): 5
+}
+
+func ExampleUndirected_TriangleCount() {
+	//   0---1
+	//   |  /|
+	//   | / |
+	//   |/  |
+	//   2---3---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	total, perNode := g.TriangleCount()
+	fmt.Println("triangles:", total)
+	fmt.Println("per node: ", perNode)
+	// Output:
+	// triangles: 2
+	// per node:  [1 2 2 1 0]
+}
+
+func TestTriangleCount(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(15)
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		for m := r.Intn(n*n/2 + 1); m > 0; m-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		total, perNode := g.TriangleCount()
+		// brute force
+		adj := func(n1, n2 int) bool {
+			ok, _ := g.HasArc(graph.NI(n1), graph.NI(n2))
+			return ok
+		}
+		want := 0
+		wantPer := make([]int, n)
+		for n1 := 0; n1 < n; n1++ {
+			for n2 := n1 + 1; n2 < n; n2++ {
+				for n3 := n2 + 1; n3 < n; n3++ {
+					if adj(n1, n2) && adj(n2, n3) && adj(n1, n3) {
+						want++
+						wantPer[n1]++
+						wantPer[n2]++
+						wantPer[n3]++
+					}
+				}
+			}
+		}
+		if total != want {
+			t.Fatal("total", total, "want", want)
+		}
+		for x := range wantPer {
+			if perNode[x] != wantPer[x] {
+				t.Fatal("node", x, "triangles", perNode[x], "want", wantPer[x])
+			}
+		}
+		// consistency with clustering coefficients
+		local, _ := g.ClusteringCoefficient()
+		for x, c := range local {
+			d := 0
+			for y := 0; y < n; y++ {
+				if y != x && adj(x, y) {
+					d++
+				}
+			}
+			if d < 2 {
+				continue
+			}
+			if p := float64(2*perNode[x]) / float64(d*(d-1)); math.Abs(p-c) > 1e-12 {
+				t.Fatal("node", x, "clustering", c, "want", p)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_UniformSpanningTree() {
+	//   0
+	//  / \
+	// 1---2
+	//  \ /
+	//   3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	f, err := g.UniformSpanningTree(rand.New(rand.NewSource(3)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("n  from")
+	for n, e := range f.Paths {
+		fmt.Println(n, " ", e.From)
+	}
+	// Output:
+	// n  from
+	// 0   -1
+	// 1   0
+	// 2   0
+	// 3   2
+}
+
+func TestUniformSpanningTree(t *testing.T) {
+	// the graph of the example has 8 spanning trees.  Check that each is
+	// sampled with roughly equal frequency.
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	r := rand.New(rand.NewSource(59))
+	const nTrees = 8
+	const samples = 8000
+	count := map[string]int{}
+	for i := 0; i < samples; i++ {
+		f, err := g.UniformSpanningTree(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n, e := range f.Paths[1:] {
+			if ok, _ := g.HasArc(e.From, graph.NI(n+1)); !ok {
+				t.Fatal("not a spanning tree:", f.Paths)
+			}
+		}
+		count[fmt.Sprint(f.Paths)]++
+	}
+	if len(count) != nTrees {
+		t.Fatal("found", len(count), "distinct trees, want", nTrees)
+	}
+	// chi-square test, 7 degrees of freedom.  24.32 is the critical
+	// value for p = .001.
+	exp := float64(samples) / nTrees
+	var x2 float64
+	for _, c := range count {
+		d := float64(c) - exp
+		x2 += d * d / exp
+	}
+	t.Log("chi-square:", x2)
+	if x2 > 24.32 {
+		t.Fatal("distribution not uniform: chi-square", x2)
+	}
+}
+
+func TestUniformSpanningTreeNotConnected(t *testing.T) {
+	g := graph.Undirected{graph.AdjacencyList{1: {2}, 2: {1}}}
+	if _, err := g.UniformSpanningTree(nil); err == nil {
+		t.Fatal("expected error for disconnected graph")
+	}
+}
+
+func ExampleUndirected_VertexCover2Approx() {
+	//   0---1---2
+	//       |
+	//       3---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(3, 4)
+	fmt.Println(g.VertexCover2Approx().Slice())
+	// Output:
+	// [0 1 3 4]
+}
+
+func TestVertexCover2Approx(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(8)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for e := r.Intn(12); e > 0; e-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		isCover := func(c graph.Bits) bool {
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if c.Bit(graph.NI(fr)) == 0 && c.Bit(to) == 0 {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		c := g.VertexCover2Approx()
+		if !isCover(c) {
+			t.Fatal("not a cover", c.Slice())
+		}
+		min := n
+		for m := 0; m < 1<<uint(n); m++ {
+			var b graph.Bits
+			for x := 0; x < n; x++ {
+				b.SetBit(graph.NI(x), uint(m>>uint(x)&1))
+			}
+			if p := b.PopCount(); p < min && isCover(b) {
+				min = p
+			}
+		}
+		if c.PopCount() > 2*min {
+			t.Fatal("cover", c.Slice(), "minimum size", min)
+		}
+	}
+}
+
+func ExampleUndirected_MaximalIndependentSet() {
+	//   0---1---2
+	//       |
+	//       3---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(3, 4)
+	fmt.Println(g.MaximalIndependentSet().Slice())
+	// Output:
+	// [0 2 3]
+}
+
+func TestMaximalIndependentSet(t *testing.T) {
+	r := rand.New(rand.NewSource(31))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(10)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for e := r.Intn(15); e > 0; e-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		s := g.MaximalIndependentSet()
+		var nb graph.Bits // nodes in s or adjacent to s
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if s.Bit(graph.NI(fr)) == 1 {
+					if s.Bit(to) == 1 {
+						t.Fatal("adjacent nodes", fr, to, "in set", s.Slice())
+					}
+					nb.SetBit(to, 1)
+				}
+			}
+		}
+		nb.Or(nb, s)
+		for n, to := range g.AdjacencyList {
+			loop := false
+			for _, to := range to {
+				loop = loop || to == graph.NI(n)
+			}
+			if nb.Bit(graph.NI(n)) == 0 && !loop {
+				t.Fatal("node", n, "could be added to", s.Slice())
+			}
+		}
+	}
+}
+
 /* shelved
 func ExampleBiconnectedComponents_Find() {
 	g := graph.AdjacencyList{